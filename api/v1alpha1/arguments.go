@@ -0,0 +1,237 @@
+package v1alpha1
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// argumentNamePattern matches the argument names the Tool CRD accepts
+var argumentNamePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]*$`)
+
+// maxArgumentNameLength is the longest argument name the Tool CRD accepts
+const maxArgumentNameLength = 63
+
+// envNamePattern matches the environment variable names an argument can be rendered as
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ArgType returns the type of the argument, defaulting to string
+func (a *Argument) ArgType() ArgumentType {
+	if a.Type == "" {
+		return ArgumentTypeString
+	}
+	return a.Type
+}
+
+// RenderMode returns how the argument is passed to the tool, defaulting to positional
+func (a *Argument) RenderMode() RenderMode {
+	if a.Render == nil || a.Render.As == "" {
+		return RenderPositional
+	}
+	return a.Render.As
+}
+
+// FlagName returns the flag used for a flag argument, --<name> by default
+func (a *Argument) FlagName() string {
+	if a.Render != nil && a.Render.Name != "" {
+		return a.Render.Name
+	}
+	return "--" + a.Name
+}
+
+// EnvName returns the environment variable set for an env argument,
+// the upper-cased argument name with dashes replaced by underscores by default
+func (a *Argument) EnvName() string {
+	if a.Render != nil && a.Render.Name != "" {
+		return a.Render.Name
+	}
+	return strings.ToUpper(strings.ReplaceAll(a.Name, "-", "_"))
+}
+
+// OmitEmpty reports whether the argument is skipped when its value is empty
+func (a *Argument) OmitEmpty() bool {
+	return a.Render != nil && a.Render.OmitEmpty
+}
+
+// ValidateValue checks a value given for the argument against its type, pattern and bounds
+func (a *Argument) ValidateValue(value string) error {
+	switch a.ArgType() {
+	case ArgumentTypeString:
+	case ArgumentTypeInt:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", value)
+		}
+		if err := a.checkIntBounds(n); err != nil {
+			return err
+		}
+	case ArgumentTypeBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("%q is not a boolean (use true or false)", value)
+		}
+	case ArgumentTypeEnum:
+		if !slices.Contains(a.Values, value) {
+			return fmt.Errorf("%q is not one of %s", value, strings.Join(a.Values, ", "))
+		}
+	case ArgumentTypeDuration:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a duration (e.g. 30s, 5m, 1h)", value)
+		}
+		if err := a.checkDurationBounds(d); err != nil {
+			return err
+		}
+	case ArgumentTypePath:
+		if value == "" || strings.ContainsRune(value, 0) {
+			return fmt.Errorf("%q is not a valid path", value)
+		}
+	default:
+		return fmt.Errorf("unknown argument type %q", a.Type)
+	}
+
+	if a.Pattern != "" {
+		re, err := regexp.Compile("^(?:" + a.Pattern + ")$")
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", a.Pattern, err)
+		}
+		if !re.MatchString(value) {
+			return fmt.Errorf("%q does not match pattern %s", value, a.Pattern)
+		}
+	}
+	return nil
+}
+
+// ValidateArgumentName checks that a name is an argument name that the Tool CRD accepts
+func ValidateArgumentName(name string) error {
+	if !argumentNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid argument name %q: it must start with a letter and contain only letters, digits, '_' and '-'", name)
+	}
+	if len(name) > maxArgumentNameLength {
+		return fmt.Errorf("invalid argument name %q: it must be at most %d characters", name, maxArgumentNameLength)
+	}
+	return nil
+}
+
+// validateDefinition checks that the argument settings are consistent with its type
+func (a *Argument) validateDefinition() error {
+	if err := ValidateArgumentName(a.Name); err != nil {
+		return err
+	}
+
+	argType := a.ArgType()
+	switch argType {
+	case ArgumentTypeString, ArgumentTypeInt, ArgumentTypeBool, ArgumentTypeEnum, ArgumentTypeDuration, ArgumentTypePath:
+	default:
+		return fmt.Errorf("unknown type %q", a.Type)
+	}
+
+	if argType == ArgumentTypeEnum && len(a.Values) == 0 {
+		return errors.New("enum arguments require values")
+	}
+	if argType != ArgumentTypeEnum && len(a.Values) > 0 {
+		return errors.New("values are only allowed for enum arguments")
+	}
+	if (a.Min != nil || a.Max != nil) && argType != ArgumentTypeInt && argType != ArgumentTypeDuration {
+		return errors.New("min and max are only allowed for int and duration arguments")
+	}
+	if a.Pattern != "" {
+		if _, err := regexp.Compile(a.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", a.Pattern, err)
+		}
+	}
+
+	switch a.RenderMode() {
+	case RenderPositional, RenderTemplate:
+		if a.Render != nil && a.Render.Name != "" {
+			return fmt.Errorf("render.name is only allowed for flag and env arguments")
+		}
+	case RenderFlag:
+		if !strings.HasPrefix(a.FlagName(), "-") {
+			return fmt.Errorf("flag name %q must start with a dash", a.FlagName())
+		}
+	case RenderEnv:
+		if !envNamePattern.MatchString(a.EnvName()) {
+			return fmt.Errorf("%q is not a valid environment variable name", a.EnvName())
+		}
+	default:
+		return fmt.Errorf("unknown render mode %q", a.Render.As)
+	}
+
+	switch argType {
+	case ArgumentTypeInt:
+		if _, err := intBound(a.Min); err != nil {
+			return fmt.Errorf("invalid min: %w", err)
+		}
+		if _, err := intBound(a.Max); err != nil {
+			return fmt.Errorf("invalid max: %w", err)
+		}
+	case ArgumentTypeDuration:
+		if _, err := durationBound(a.Min); err != nil {
+			return fmt.Errorf("invalid min: %w", err)
+		}
+		if _, err := durationBound(a.Max); err != nil {
+			return fmt.Errorf("invalid max: %w", err)
+		}
+	}
+
+	if a.Default != "" {
+		if a.Required {
+			return errors.New("a required argument cannot have a default value")
+		}
+		if err := a.ValidateValue(a.Default); err != nil {
+			return fmt.Errorf("invalid default: %w", err)
+		}
+	}
+	return nil
+}
+
+// checkIntBounds checks an int value against the argument's min and max
+func (a *Argument) checkIntBounds(n int64) error {
+	if min, err := intBound(a.Min); err == nil && a.Min != nil && n < min {
+		return fmt.Errorf("%d is less than the minimum %d", n, min)
+	}
+	if max, err := intBound(a.Max); err == nil && a.Max != nil && n > max {
+		return fmt.Errorf("%d is greater than the maximum %d", n, max)
+	}
+	return nil
+}
+
+// checkDurationBounds checks a duration value against the argument's min and max
+func (a *Argument) checkDurationBounds(d time.Duration) error {
+	if min, err := durationBound(a.Min); err == nil && a.Min != nil && d < min {
+		return fmt.Errorf("%s is shorter than the minimum %s", d, min)
+	}
+	if max, err := durationBound(a.Max); err == nil && a.Max != nil && d > max {
+		return fmt.Errorf("%s is longer than the maximum %s", d, max)
+	}
+	return nil
+}
+
+// intBound returns the integer value of a min or max setting
+func intBound(bound *intstr.IntOrString) (int64, error) {
+	if bound == nil {
+		return 0, nil
+	}
+	if bound.Type == intstr.Int {
+		return int64(bound.IntVal), nil
+	}
+	return strconv.ParseInt(bound.StrVal, 10, 64)
+}
+
+// durationBound returns the duration of a min or max setting.
+// Plain integers are read as seconds.
+func durationBound(bound *intstr.IntOrString) (time.Duration, error) {
+	if bound == nil {
+		return 0, nil
+	}
+	if bound.Type == intstr.Int {
+		return time.Duration(bound.IntVal) * time.Second, nil
+	}
+	return time.ParseDuration(bound.StrVal)
+}
@@ -0,0 +1,146 @@
+package v1alpha1
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestValidateValue(t *testing.T) {
+	intBounds := Argument{Name: "retries", Type: ArgumentTypeInt, Min: intOrString(intstr.FromInt32(1)), Max: intOrString(intstr.FromString("10"))}
+	durationBounds := Argument{Name: "timeout", Type: ArgumentTypeDuration, Min: intOrString(intstr.FromInt32(30)), Max: intOrString(intstr.FromString("1h"))}
+	enum := Argument{Name: "level", Type: ArgumentTypeEnum, Values: []string{"debug", "info"}}
+	pattern := Argument{Name: "tag", Pattern: `v[0-9]+`}
+
+	tests := []struct {
+		name    string
+		arg     Argument
+		value   string
+		wantErr string
+	}{
+		{name: "string", arg: Argument{Name: "message"}, value: "any value"},
+		{name: "empty string", arg: Argument{Name: "message"}, value: ""},
+
+		{name: "int", arg: Argument{Name: "n", Type: ArgumentTypeInt}, value: "-42"},
+		{name: "int not a number", arg: Argument{Name: "n", Type: ArgumentTypeInt}, value: "4.2", wantErr: `"4.2" is not an integer`},
+		{name: "int empty", arg: Argument{Name: "n", Type: ArgumentTypeInt}, value: "", wantErr: "is not an integer"},
+		{name: "int at minimum", arg: intBounds, value: "1"},
+		{name: "int below minimum", arg: intBounds, value: "0", wantErr: "0 is less than the minimum 1"},
+		{name: "int at maximum", arg: intBounds, value: "10"},
+		{name: "int above maximum", arg: intBounds, value: "11", wantErr: "11 is greater than the maximum 10"},
+
+		{name: "bool true", arg: Argument{Name: "dry-run", Type: ArgumentTypeBool}, value: "true"},
+		{name: "bool numeric", arg: Argument{Name: "dry-run", Type: ArgumentTypeBool}, value: "0"},
+		{name: "bool invalid", arg: Argument{Name: "dry-run", Type: ArgumentTypeBool}, value: "yes", wantErr: `"yes" is not a boolean`},
+
+		{name: "enum value", arg: enum, value: "info"},
+		{name: "enum is case sensitive", arg: enum, value: "INFO", wantErr: `"INFO" is not one of debug, info`},
+		{name: "enum empty", arg: enum, value: "", wantErr: "is not one of"},
+
+		{name: "duration", arg: Argument{Name: "timeout", Type: ArgumentTypeDuration}, value: "1h30m"},
+		{name: "duration without unit", arg: Argument{Name: "timeout", Type: ArgumentTypeDuration}, value: "30", wantErr: `"30" is not a duration`},
+		{name: "duration at minimum", arg: durationBounds, value: "30s"},
+		{name: "duration below minimum", arg: durationBounds, value: "29s", wantErr: "29s is shorter than the minimum 30s"},
+		{name: "duration at maximum", arg: durationBounds, value: "60m"},
+		{name: "duration above maximum", arg: durationBounds, value: "1h0m1s", wantErr: "1h0m1s is longer than the maximum 1h0m0s"},
+
+		{name: "path", arg: Argument{Name: "input", Type: ArgumentTypePath}, value: "/data/input.csv"},
+		{name: "path empty", arg: Argument{Name: "input", Type: ArgumentTypePath}, value: "", wantErr: "is not a valid path"},
+		{name: "path with NUL", arg: Argument{Name: "input", Type: ArgumentTypePath}, value: "a\x00b", wantErr: "is not a valid path"},
+
+		{name: "pattern match", arg: pattern, value: "v12"},
+		{name: "pattern is anchored at the start", arg: pattern, value: "xv12", wantErr: `"xv12" does not match pattern v[0-9]+`},
+		{name: "pattern is anchored at the end", arg: pattern, value: "v12x", wantErr: "does not match pattern"},
+		{name: "pattern alternatives are anchored", arg: Argument{Name: "env", Pattern: "dev|prod"}, value: "devel", wantErr: "does not match pattern"},
+		{name: "pattern after type check", arg: Argument{Name: "n", Type: ArgumentTypeInt, Pattern: "[0-9]{2}"}, value: "7", wantErr: "does not match pattern"},
+		{name: "pattern on enum", arg: Argument{Name: "level", Type: ArgumentTypeEnum, Values: []string{"debug", "info"}, Pattern: "d.*"}, value: "info", wantErr: "does not match pattern"},
+		{name: "invalid pattern", arg: Argument{Name: "tag", Pattern: "("}, value: "v1", wantErr: "invalid pattern"},
+
+		{name: "unknown type", arg: Argument{Name: "n", Type: "float"}, value: "1.5", wantErr: `unknown argument type "float"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.arg.ValidateValue(tt.value)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("ValidateValue(%q) error = %v, want nil", tt.value, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ValidateValue(%q) error = %v, want it to contain %q", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateArgumentName(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{"input", false},
+		{"Input2", false},
+		{"log-level", false},
+		{"log_level", false},
+		{"a", false},
+		{strings.Repeat("a", 63), false},
+		{strings.Repeat("a", 64), true},
+		{"", true},
+		{"2fast", true},
+		{"-flag", true},
+		{"_private", true},
+		{"log level", true},
+		{"log.level", true},
+		{"naïve", true},
+	}
+	for _, tt := range tests {
+		if err := ValidateArgumentName(tt.name); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateArgumentName(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateDefinition(t *testing.T) {
+	tests := []struct {
+		name    string
+		arg     Argument
+		wantErr string
+	}{
+		{name: "string", arg: Argument{Name: "message"}},
+		{name: "invalid name", arg: Argument{Name: "log level"}, wantErr: "invalid argument name"},
+		{name: "enum without values", arg: Argument{Name: "level", Type: ArgumentTypeEnum}, wantErr: "enum arguments require values"},
+		{name: "values on a string", arg: Argument{Name: "level", Values: []string{"a"}}, wantErr: "values are only allowed for enum arguments"},
+		{name: "bounds on a string", arg: Argument{Name: "n", Min: intOrString(intstr.FromInt32(1))}, wantErr: "min and max are only allowed"},
+		{name: "invalid int bound", arg: Argument{Name: "n", Type: ArgumentTypeInt, Max: intOrString(intstr.FromString("ten"))}, wantErr: "invalid max"},
+		{name: "invalid duration bound", arg: Argument{Name: "t", Type: ArgumentTypeDuration, Min: intOrString(intstr.FromString("soon"))}, wantErr: "invalid min"},
+		{name: "valid default", arg: Argument{Name: "n", Type: ArgumentTypeInt, Default: "3"}},
+		{name: "invalid default", arg: Argument{Name: "n", Type: ArgumentTypeInt, Default: "three"}, wantErr: "invalid default"},
+		{name: "default out of bounds", arg: Argument{Name: "n", Type: ArgumentTypeInt, Max: intOrString(intstr.FromInt32(2)), Default: "3"}, wantErr: "invalid default"},
+		{name: "required with default", arg: Argument{Name: "n", Required: true, Default: "3"}, wantErr: "a required argument cannot have a default value"},
+		{name: "invalid pattern", arg: Argument{Name: "tag", Pattern: "["}, wantErr: "invalid pattern"},
+		{name: "flag without dash", arg: Argument{Name: "out", Render: &ArgumentRender{As: RenderFlag, Name: "out"}}, wantErr: "must start with a dash"},
+		{name: "invalid env name", arg: Argument{Name: "out", Render: &ArgumentRender{As: RenderEnv, Name: "1OUT"}}, wantErr: "not a valid environment variable name"},
+		{name: "name on a positional argument", arg: Argument{Name: "out", Render: &ArgumentRender{Name: "--out"}}, wantErr: "render.name is only allowed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.arg.validateDefinition()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validateDefinition() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("validateDefinition() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// intOrString returns a pointer to a min or max setting
+func intOrString(value intstr.IntOrString) *intstr.IntOrString {
+	return &value
+}
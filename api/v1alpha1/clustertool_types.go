@@ -0,0 +1,61 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterToolKind is the kind of the ClusterTool resource
+const ClusterToolKind = "ClusterTool"
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+
+// ClusterTool is a Tool that is available in every namespace.
+// A namespaced Tool with the same name takes precedence over it.
+type ClusterTool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ToolSpec   `json:"spec"`
+	Status ToolStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterToolList is a list of ClusterTool objects
+type ClusterToolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ClusterTool `json:"items"`
+}
+
+// NewClusterTool returns an empty ClusterTool with its type metadata set
+func NewClusterTool(name string) *ClusterTool {
+	return &ClusterTool{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: GroupVersion.String(),
+			Kind:       ClusterToolKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+}
+
+// AsTool returns a copy of the ClusterTool as a Tool, so that it can be run like one.
+// The copy keeps the ClusterTool kind, see Tool.IsClusterTool.
+func (c *ClusterTool) AsTool() *Tool {
+	return &Tool{
+		TypeMeta:   c.TypeMeta,
+		ObjectMeta: *c.ObjectMeta.DeepCopy(),
+		Spec:       *c.Spec.DeepCopy(),
+		Status:     *c.Status.DeepCopy(),
+	}
+}
+
+// IsClusterTool reports whether the Tool was obtained from a ClusterTool with AsTool
+func (t *Tool) IsClusterTool() bool {
+	return t.Kind == ClusterToolKind
+}
@@ -0,0 +1,137 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ToolFromUnstructured converts an unstructured object into a Tool.
+// Fields of the wrong type and missing required fields are reported as errors.
+func ToolFromUnstructured(u *unstructured.Unstructured) (*Tool, error) {
+	tool, err := DecodeTool(u)
+	if err != nil {
+		return nil, err
+	}
+	if err := tool.Validate(); err != nil {
+		return nil, err
+	}
+	return tool, nil
+}
+
+// DecodeTool converts an unstructured object into a Tool without validating it, for
+// the commands that have to handle tools made invalid by changes outside of rapt.
+// Only fields of the wrong type are reported as errors.
+func DecodeTool(u *unstructured.Unstructured) (*Tool, error) {
+	if u.GetKind() != ToolKind {
+		return nil, fmt.Errorf("unexpected kind %q, expected %q", u.GetKind(), ToolKind)
+	}
+
+	var tool Tool
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &tool); err != nil {
+		return nil, fmt.Errorf("invalid tool %s: %w", u.GetName(), err)
+	}
+	return &tool, nil
+}
+
+// ToolsFromUnstructuredList converts the items of an unstructured list into Tools.
+// An invalid item does not stop the others: it is left out and reported with an error
+// that names its namespace and name.
+func ToolsFromUnstructuredList(list *unstructured.UnstructuredList) ([]Tool, []error) {
+	var tools []Tool
+	var errs []error
+	for i := range list.Items {
+		tool, err := ToolFromUnstructured(&list.Items[i])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s/%s: %w", list.Items[i].GetNamespace(), list.Items[i].GetName(), err))
+			continue
+		}
+		tools = append(tools, *tool)
+	}
+	return tools, errs
+}
+
+// ToUnstructured converts the Tool into an unstructured object
+func (t *Tool) ToUnstructured() (*unstructured.Unstructured, error) {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(t)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert tool %s: %w", t.Name, err)
+	}
+	u := &unstructured.Unstructured{Object: obj}
+	u.SetAPIVersion(GroupVersion.String())
+	u.SetKind(ToolKind)
+	return u, nil
+}
+
+// ClusterToolFromUnstructured converts an unstructured object into a ClusterTool.
+// Fields of the wrong type and missing required fields are reported as errors.
+func ClusterToolFromUnstructured(u *unstructured.Unstructured) (*ClusterTool, error) {
+	if u.GetKind() != ClusterToolKind {
+		return nil, fmt.Errorf("unexpected kind %q, expected %q", u.GetKind(), ClusterToolKind)
+	}
+
+	var clusterTool ClusterTool
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &clusterTool); err != nil {
+		return nil, fmt.Errorf("invalid cluster tool %s: %w", u.GetName(), err)
+	}
+	if err := clusterTool.AsTool().Validate(); err != nil {
+		return nil, err
+	}
+	return &clusterTool, nil
+}
+
+// ClusterToolsFromUnstructuredList converts the items of an unstructured list into ClusterTools.
+// An invalid item does not stop the others: it is left out and reported with an error.
+func ClusterToolsFromUnstructuredList(list *unstructured.UnstructuredList) ([]ClusterTool, []error) {
+	var clusterTools []ClusterTool
+	var errs []error
+	for i := range list.Items {
+		clusterTool, err := ClusterToolFromUnstructured(&list.Items[i])
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		clusterTools = append(clusterTools, *clusterTool)
+	}
+	return clusterTools, errs
+}
+
+// ToUnstructured converts the ClusterTool into an unstructured object
+func (c *ClusterTool) ToUnstructured() (*unstructured.Unstructured, error) {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert cluster tool %s: %w", c.Name, err)
+	}
+	u := &unstructured.Unstructured{Object: obj}
+	u.SetAPIVersion(GroupVersion.String())
+	u.SetKind(ClusterToolKind)
+	return u, nil
+}
+
+// ToolRevisionsFromUnstructuredList converts every item of an unstructured list into a ToolRevision.
+// Revisions are snapshots of specs that were valid when they were taken, so they are not validated again.
+func ToolRevisionsFromUnstructuredList(list *unstructured.UnstructuredList) ([]ToolRevision, error) {
+	revisions := make([]ToolRevision, len(list.Items))
+	for i, item := range list.Items {
+		if item.GetKind() != ToolRevisionKind {
+			return nil, fmt.Errorf("unexpected kind %q, expected %q", item.GetKind(), ToolRevisionKind)
+		}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &revisions[i]); err != nil {
+			return nil, fmt.Errorf("invalid tool revision %s: %w", item.GetName(), err)
+		}
+	}
+	return revisions, nil
+}
+
+// ToUnstructured converts the ToolRevision into an unstructured object
+func (r *ToolRevision) ToUnstructured() (*unstructured.Unstructured, error) {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert tool revision %s: %w", r.Name, err)
+	}
+	u := &unstructured.Unstructured{Object: obj}
+	u.SetAPIVersion(GroupVersion.String())
+	u.SetKind(ToolRevisionKind)
+	return u, nil
+}
@@ -0,0 +1,56 @@
+package v1alpha1
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestToolsFromUnstructuredList(t *testing.T) {
+	item := func(namespace, name, image string) unstructured.Unstructured {
+		u := unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"jobTemplate": map[string]interface{}{"image": image}},
+		}}
+		u.SetAPIVersion(GroupVersion.String())
+		u.SetKind(ToolKind)
+		u.SetNamespace(namespace)
+		u.SetName(name)
+		return u
+	}
+	list := &unstructured.UnstructuredList{Items: []unstructured.Unstructured{
+		item("default", "report", "alpine:3.20"),
+		item("team", "broken", ""),
+		item("team", "backup", "alpine:3.20"),
+	}}
+
+	tools, errs := ToolsFromUnstructuredList(list)
+	if len(tools) != 2 || tools[0].Name != "report" || tools[1].Name != "backup" {
+		t.Errorf("tools = %+v, want report and backup", tools)
+	}
+	if len(errs) != 1 || !strings.HasPrefix(errs[0].Error(), "team/broken: invalid tool broken") {
+		t.Errorf("errs = %v, want one error for team/broken", errs)
+	}
+}
+
+func TestClusterToolsFromUnstructuredList(t *testing.T) {
+	valid, err := NewClusterTool("report").ToUnstructured()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := unstructured.SetNestedField(valid.Object, "alpine:3.20", "spec", "jobTemplate", "image"); err != nil {
+		t.Fatal(err)
+	}
+	invalid, err := NewClusterTool("broken").ToUnstructured()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clusterTools, errs := ClusterToolsFromUnstructuredList(&unstructured.UnstructuredList{Items: []unstructured.Unstructured{*invalid, *valid}})
+	if len(clusterTools) != 1 || clusterTools[0].Name != "report" {
+		t.Errorf("clusterTools = %+v, want report", clusterTools)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "broken") {
+		t.Errorf("errs = %v, want one error for broken", errs)
+	}
+}
@@ -0,0 +1,160 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"maps"
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// RefKind returns the kind of the referenced tool, defaulting to Tool
+func (r *ToolReference) RefKind() string {
+	if r.Kind == "" {
+		return ToolKind
+	}
+	return r.Kind
+}
+
+// String returns the reference in the form Kind/name
+func (r *ToolReference) String() string {
+	return r.RefKind() + "/" + r.Name
+}
+
+// validateExtends checks the reference to the base tool
+func (t *Tool) validateExtends() error {
+	ref := t.Spec.Extends
+	if ref.Name == "" {
+		return fmt.Errorf("spec.extends.name is required")
+	}
+	switch ref.RefKind() {
+	case ToolKind:
+		if t.IsClusterTool() {
+			return fmt.Errorf("spec.extends: a ClusterTool can only extend another ClusterTool")
+		}
+	case ClusterToolKind:
+	default:
+		return fmt.Errorf("spec.extends.kind must be %s or %s, got %q", ToolKind, ClusterToolKind, ref.Kind)
+	}
+	return nil
+}
+
+// MergeToolSpec returns the effective spec of a tool that overrides base.
+// The result does not extend anything. Fields are merged as follows:
+//   - arguments, env, volumes, init containers and sidecars are merged by name,
+//     and volume mounts by mount path: entries of the override replace base entries
+//     with the same key, new entries are appended
+//   - envFrom and tolerations are appended to those of the base
+//   - nodeSelector labels are merged, the override wins
+//   - command and args replace the base ones when the override sets them
+//   - every other field replaces the base field when it is set in the override
+func MergeToolSpec(base, override *ToolSpec) ToolSpec {
+	merged := *base.DeepCopy()
+	spec := override.DeepCopy()
+
+	merged.Extends = nil
+	if spec.Help != "" {
+		merged.Help = spec.Help
+	}
+	merged.Arguments = mergeByKey(merged.Arguments, spec.Arguments, func(arg Argument) string { return arg.Name })
+
+	jobTemplate := &merged.JobTemplate
+	o := spec.JobTemplate
+	if o.Image != "" {
+		jobTemplate.Image = o.Image
+	}
+	if len(o.Command) > 0 {
+		jobTemplate.Command = o.Command
+	}
+	if len(o.Args) > 0 {
+		jobTemplate.Args = o.Args
+	}
+	jobTemplate.Env = mergeByKey(jobTemplate.Env, o.Env, func(env corev1.EnvVar) string { return env.Name })
+	jobTemplate.EnvFrom = append(jobTemplate.EnvFrom, o.EnvFrom...)
+	if o.Resources != nil {
+		jobTemplate.Resources = o.Resources
+	}
+
+	if len(o.NodeSelector) > 0 {
+		if jobTemplate.NodeSelector == nil {
+			jobTemplate.NodeSelector = make(map[string]string)
+		}
+		maps.Copy(jobTemplate.NodeSelector, o.NodeSelector)
+	}
+	jobTemplate.Tolerations = append(jobTemplate.Tolerations, o.Tolerations...)
+	if o.Affinity != nil {
+		jobTemplate.Affinity = o.Affinity
+	}
+	if o.PriorityClassName != "" {
+		jobTemplate.PriorityClassName = o.PriorityClassName
+	}
+	if o.RuntimeClassName != nil {
+		jobTemplate.RuntimeClassName = o.RuntimeClassName
+	}
+
+	if o.ServiceAccountName != "" {
+		jobTemplate.ServiceAccountName = o.ServiceAccountName
+	}
+	if o.AutomountServiceAccountToken != nil {
+		jobTemplate.AutomountServiceAccountToken = o.AutomountServiceAccountToken
+	}
+	if o.SecurityProfile != "" {
+		jobTemplate.SecurityProfile = o.SecurityProfile
+	}
+	if o.PodSecurityContext != nil {
+		jobTemplate.PodSecurityContext = o.PodSecurityContext
+	}
+	if o.SecurityContext != nil {
+		jobTemplate.SecurityContext = o.SecurityContext
+	}
+
+	jobTemplate.Volumes = mergeByKey(jobTemplate.Volumes, o.Volumes, func(volume corev1.Volume) string { return volume.Name })
+	jobTemplate.VolumeMounts = mergeByKey(jobTemplate.VolumeMounts, o.VolumeMounts, func(mount corev1.VolumeMount) string {
+		return path.Clean(mount.MountPath)
+	})
+	jobTemplate.InitContainers = mergeByKey(jobTemplate.InitContainers, o.InitContainers, containerName)
+	jobTemplate.Sidecars = mergeByKey(jobTemplate.Sidecars, o.Sidecars, containerName)
+
+	if o.BackoffLimit != nil {
+		jobTemplate.BackoffLimit = o.BackoffLimit
+	}
+	if o.ActiveDeadlineSeconds != nil {
+		jobTemplate.ActiveDeadlineSeconds = o.ActiveDeadlineSeconds
+	}
+	if o.TTLSecondsAfterFinished != nil {
+		jobTemplate.TTLSecondsAfterFinished = o.TTLSecondsAfterFinished
+	}
+	if o.PodFailurePolicy != nil {
+		jobTemplate.PodFailurePolicy = o.PodFailurePolicy
+	}
+
+	return merged
+}
+
+// mergeByKey replaces the base items that have the same key as an override item
+// and appends the remaining override items in their order
+func mergeByKey[T any](base, override []T, key func(T) string) []T {
+	if len(override) == 0 {
+		return base
+	}
+
+	positions := make(map[string]int, len(base))
+	for i, item := range base {
+		positions[key(item)] = i
+	}
+	merged := base
+	for _, item := range override {
+		if i, exists := positions[key(item)]; exists {
+			merged[i] = item
+			continue
+		}
+		positions[key(item)] = len(merged)
+		merged = append(merged, item)
+	}
+	return merged
+}
+
+// containerName returns the name of a container
+func containerName(container corev1.Container) string {
+	return container.Name
+}
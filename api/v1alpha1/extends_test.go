@@ -0,0 +1,179 @@
+package v1alpha1
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestMergeByKey(t *testing.T) {
+	type item struct{ key, value string }
+	key := func(i item) string { return i.key }
+
+	tests := []struct {
+		name     string
+		base     []item
+		override []item
+		want     []item
+	}{
+		{name: "no override", base: []item{{"a", "1"}}, want: []item{{"a", "1"}}},
+		{name: "no base", override: []item{{"a", "1"}}, want: []item{{"a", "1"}}},
+		{name: "override replaces in place", base: []item{{"a", "1"}, {"b", "2"}, {"c", "3"}}, override: []item{{"b", "x"}}, want: []item{{"a", "1"}, {"b", "x"}, {"c", "3"}}},
+		{name: "new items are appended in order", base: []item{{"a", "1"}}, override: []item{{"c", "3"}, {"b", "2"}}, want: []item{{"a", "1"}, {"c", "3"}, {"b", "2"}}},
+		{name: "replace and append", base: []item{{"a", "1"}, {"b", "2"}}, override: []item{{"c", "3"}, {"a", "x"}}, want: []item{{"a", "x"}, {"b", "2"}, {"c", "3"}}},
+		{name: "last duplicate in the override wins", base: []item{{"a", "1"}}, override: []item{{"b", "2"}, {"b", "3"}}, want: []item{{"a", "1"}, {"b", "3"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mergeByKey(slices.Clone(tt.base), tt.override, key)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("mergeByKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeToolSpec(t *testing.T) {
+	base := ToolSpec{
+		Help:      "base help",
+		Arguments: []Argument{{Name: "input", Required: true}, {Name: "level", Default: "info"}},
+		JobTemplate: JobTemplate{
+			Image:              "alpine:3.20",
+			Command:            []string{"run"},
+			Args:               []string{"--base"},
+			Env:                []corev1.EnvVar{{Name: "A", Value: "1"}, {Name: "B", Value: "2"}},
+			EnvFrom:            []corev1.EnvFromSource{{Prefix: "BASE_"}},
+			NodeSelector:       map[string]string{"zone": "a", "disk": "ssd"},
+			Tolerations:        []corev1.Toleration{{Key: "base"}},
+			ServiceAccountName: "base-sa",
+			BackoffLimit:       int32Ptr(3),
+			Resources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+			},
+			Volumes:        []corev1.Volume{{Name: "data"}},
+			VolumeMounts:   []corev1.VolumeMount{{Name: "data", MountPath: "/data/"}},
+			InitContainers: []corev1.Container{{Name: "setup", Image: "setup:1"}},
+			Sidecars:       []corev1.Container{{Name: "proxy", Image: "proxy:1"}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		override ToolSpec
+		check    func(t *testing.T, merged ToolSpec)
+	}{
+		{
+			name:     "empty override keeps the base",
+			override: ToolSpec{Extends: &ToolReference{Name: "base"}},
+			check: func(t *testing.T, merged ToolSpec) {
+				want := *base.DeepCopy()
+				if !reflect.DeepEqual(merged, want) {
+					t.Errorf("merged = %+v, want the base %+v", merged, want)
+				}
+			},
+		},
+		{
+			name: "scalars are overridden",
+			override: ToolSpec{Help: "help", JobTemplate: JobTemplate{
+				Image: "alpine:3.21", ServiceAccountName: "sa", BackoffLimit: int32Ptr(0),
+				Resources: &corev1.ResourceRequirements{},
+			}},
+			check: func(t *testing.T, merged ToolSpec) {
+				jt := merged.JobTemplate
+				if merged.Help != "help" || jt.Image != "alpine:3.21" || jt.ServiceAccountName != "sa" || *jt.BackoffLimit != 0 {
+					t.Errorf("merged = %+v, want the override scalars", merged)
+				}
+				if jt.Resources == nil || len(jt.Resources.Requests) != 0 {
+					t.Errorf("resources = %v, want them replaced as a whole", jt.Resources)
+				}
+			},
+		},
+		{
+			name:     "command and args are replaced",
+			override: ToolSpec{JobTemplate: JobTemplate{Command: []string{"other"}, Args: []string{"--override"}}},
+			check: func(t *testing.T, merged ToolSpec) {
+				if !slices.Equal(merged.JobTemplate.Command, []string{"other"}) || !slices.Equal(merged.JobTemplate.Args, []string{"--override"}) {
+					t.Errorf("command, args = %q, %q", merged.JobTemplate.Command, merged.JobTemplate.Args)
+				}
+			},
+		},
+		{
+			name: "keyed lists are merged",
+			override: ToolSpec{
+				Arguments: []Argument{{Name: "level", Default: "debug"}, {Name: "output"}},
+				JobTemplate: JobTemplate{
+					Env:            []corev1.EnvVar{{Name: "B", Value: "x"}, {Name: "C", Value: "3"}},
+					VolumeMounts:   []corev1.VolumeMount{{Name: "data", MountPath: "/data", ReadOnly: true}},
+					InitContainers: []corev1.Container{{Name: "setup", Image: "setup:2"}},
+					Sidecars:       []corev1.Container{{Name: "logger", Image: "logger:1"}},
+				},
+			},
+			check: func(t *testing.T, merged ToolSpec) {
+				wantArgs := []Argument{{Name: "input", Required: true}, {Name: "level", Default: "debug"}, {Name: "output"}}
+				if !reflect.DeepEqual(merged.Arguments, wantArgs) {
+					t.Errorf("arguments = %+v, want %+v", merged.Arguments, wantArgs)
+				}
+				jt := merged.JobTemplate
+				wantEnv := []corev1.EnvVar{{Name: "A", Value: "1"}, {Name: "B", Value: "x"}, {Name: "C", Value: "3"}}
+				if !reflect.DeepEqual(jt.Env, wantEnv) {
+					t.Errorf("env = %+v, want %+v", jt.Env, wantEnv)
+				}
+				if len(jt.VolumeMounts) != 1 || !jt.VolumeMounts[0].ReadOnly {
+					t.Errorf("volumeMounts = %+v, want /data replaced by its cleaned path", jt.VolumeMounts)
+				}
+				if len(jt.InitContainers) != 1 || jt.InitContainers[0].Image != "setup:2" {
+					t.Errorf("initContainers = %+v, want setup replaced", jt.InitContainers)
+				}
+				if len(jt.Sidecars) != 2 || jt.Sidecars[1].Name != "logger" {
+					t.Errorf("sidecars = %+v, want logger appended", jt.Sidecars)
+				}
+			},
+		},
+		{
+			name: "envFrom and tolerations are appended",
+			override: ToolSpec{JobTemplate: JobTemplate{
+				EnvFrom:     []corev1.EnvFromSource{{Prefix: "TOOL_"}},
+				Tolerations: []corev1.Toleration{{Key: "tool"}},
+			}},
+			check: func(t *testing.T, merged ToolSpec) {
+				jt := merged.JobTemplate
+				if len(jt.EnvFrom) != 2 || jt.EnvFrom[1].Prefix != "TOOL_" {
+					t.Errorf("envFrom = %+v, want the override appended", jt.EnvFrom)
+				}
+				if len(jt.Tolerations) != 2 || jt.Tolerations[1].Key != "tool" {
+					t.Errorf("tolerations = %+v, want the override appended", jt.Tolerations)
+				}
+			},
+		},
+		{
+			name:     "node selectors are merged",
+			override: ToolSpec{JobTemplate: JobTemplate{NodeSelector: map[string]string{"zone": "b", "gpu": "true"}}},
+			check: func(t *testing.T, merged ToolSpec) {
+				want := map[string]string{"zone": "b", "disk": "ssd", "gpu": "true"}
+				if !reflect.DeepEqual(merged.JobTemplate.NodeSelector, want) {
+					t.Errorf("nodeSelector = %v, want %v", merged.JobTemplate.NodeSelector, want)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := base.DeepCopy()
+			merged := MergeToolSpec(&base, &tt.override)
+
+			if merged.Extends != nil {
+				t.Errorf("extends = %v, want nil", merged.Extends)
+			}
+			if !reflect.DeepEqual(&base, original) {
+				t.Errorf("the base was modified")
+			}
+			tt.check(t, merged)
+		})
+	}
+}
+
+// int32Ptr returns a pointer to an int32
+func int32Ptr(i int32) *int32 { return &i }
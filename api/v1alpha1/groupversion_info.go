@@ -0,0 +1,37 @@
+// Package v1alpha1 contains the Go types of the rapt.dev/v1alpha1 API group.
+// +kubebuilder:object:generate=true
+// +groupName=rapt.dev
+package v1alpha1
+
+//go:generate go run sigs.k8s.io/controller-tools/cmd/controller-gen@v0.18.0 object paths=.
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GroupVersion is the group and version used to register these objects
+var GroupVersion = schema.GroupVersion{Group: "rapt.dev", Version: "v1alpha1"}
+
+// ToolResource is the resource used to access Tool objects through the dynamic client
+var ToolResource = GroupVersion.WithResource("tools")
+
+// ClusterToolResource is the resource used to access ClusterTool objects through the dynamic client
+var ClusterToolResource = GroupVersion.WithResource("clustertools")
+
+// ToolRevisionResource is the resource used to access ToolRevision objects through the dynamic client
+var ToolRevisionResource = GroupVersion.WithResource("toolrevisions")
+
+var (
+	// SchemeBuilder collects the functions that add the types of this group to a scheme
+	SchemeBuilder = runtime.NewSchemeBuilder(addKnownTypes)
+	// AddToScheme adds the types of this group to a scheme
+	AddToScheme = SchemeBuilder.AddToScheme
+)
+
+func addKnownTypes(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(GroupVersion, &Tool{}, &ToolList{}, &ClusterTool{}, &ClusterToolList{}, &ToolRevision{}, &ToolRevisionList{})
+	metav1.AddToGroupVersion(scheme, GroupVersion)
+	return nil
+}
@@ -0,0 +1,34 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RecordRun records a finished run in the status
+func (s *ToolStatus) RecordRun(jobName string, started metav1.Time, result RunResult) {
+	s.LastRunTime = &started
+	s.LastRunResult = result
+	s.LastJobName = jobName
+	switch result {
+	case RunSucceeded:
+		s.SucceededRuns++
+	case RunFailed:
+		s.FailedRuns++
+	}
+}
+
+// HasRuns reports whether any finished run has been recorded
+func (s *ToolStatus) HasRuns() bool {
+	return s.LastRunTime != nil || s.SucceededRuns > 0 || s.FailedRuns > 0
+}
+
+// RecordArgumentCount records the number of arguments declared in spec.
+// It reports whether the status changed.
+func (s *ToolStatus) RecordArgumentCount(spec *ToolSpec) bool {
+	count := int32(len(spec.Arguments))
+	if s.ArgumentCount != nil && *s.ArgumentCount == count {
+		return false
+	}
+	s.ArgumentCount = &count
+	return true
+}
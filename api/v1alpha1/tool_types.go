@@ -0,0 +1,219 @@
+package v1alpha1
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// ToolKind is the kind of the Tool resource
+const ToolKind = "Tool"
+
+// ToolContainerName is the name of the container running the tool itself
+const ToolContainerName = "tool"
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// Tool is a predefined job that Rapt can run in the cluster
+type Tool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ToolSpec   `json:"spec"`
+	Status ToolStatus `json:"status,omitempty"`
+}
+
+// ToolSpec defines the behavior of a tool
+type ToolSpec struct {
+	// Extends refers to a base tool whose spec this one overrides, see MergeToolSpec
+	Extends *ToolReference `json:"extends,omitempty"`
+	// Help is the help text displayed for the tool
+	Help string `json:"help,omitempty"`
+	// Arguments is the list of arguments the tool accepts
+	Arguments []Argument `json:"arguments,omitempty"`
+	// JobTemplate defines how the tool's job is executed
+	JobTemplate JobTemplate `json:"jobTemplate"`
+}
+
+// ToolReference refers to a Tool in the same namespace or to a ClusterTool
+type ToolReference struct {
+	// Kind is Tool or ClusterTool, Tool when unset
+	Kind string `json:"kind,omitempty"`
+	// Name is the name of the referenced tool
+	Name string `json:"name"`
+}
+
+// Argument is a parameter accepted by a tool
+type Argument struct {
+	// Name is the argument name
+	Name string `json:"name"`
+	// Description is a human-readable description of the argument
+	Description string `json:"description,omitempty"`
+	// Required marks the argument as mandatory
+	Required bool `json:"required,omitempty"`
+	// Default is the value used when the argument is not provided
+	Default string `json:"default,omitempty"`
+	// Type is the type of the argument value, string when unset
+	Type ArgumentType `json:"type,omitempty"`
+	// Values lists the allowed values of an enum argument
+	Values []string `json:"values,omitempty"`
+	// Pattern is a regular expression the whole value must match
+	Pattern string `json:"pattern,omitempty"`
+	// Min is the minimum of an int or duration argument
+	Min *intstr.IntOrString `json:"min,omitempty"`
+	// Max is the maximum of an int or duration argument
+	Max *intstr.IntOrString `json:"max,omitempty"`
+	// Render controls how the value is passed to the tool
+	Render *ArgumentRender `json:"render,omitempty"`
+}
+
+// ArgumentRender controls how an argument value is passed to the tool
+type ArgumentRender struct {
+	// As selects the rendering mode, positional when unset
+	As RenderMode `json:"as,omitempty"`
+	// Name is the flag or environment variable name
+	Name string `json:"name,omitempty"`
+	// OmitEmpty skips the argument when its value is empty
+	OmitEmpty bool `json:"omitEmpty,omitempty"`
+}
+
+// RenderMode is the way an argument value is passed to the tool
+type RenderMode string
+
+const (
+	RenderPositional RenderMode = "positional"
+	RenderFlag       RenderMode = "flag"
+	RenderEnv        RenderMode = "env"
+	RenderTemplate   RenderMode = "template"
+)
+
+// ArgumentType is the type of an argument value
+type ArgumentType string
+
+const (
+	ArgumentTypeString   ArgumentType = "string"
+	ArgumentTypeInt      ArgumentType = "int"
+	ArgumentTypeBool     ArgumentType = "bool"
+	ArgumentTypeEnum     ArgumentType = "enum"
+	ArgumentTypeDuration ArgumentType = "duration"
+	ArgumentTypePath     ArgumentType = "path"
+)
+
+// JobTemplate describes the container run for each tool execution
+type JobTemplate struct {
+	// Image is the container image to run
+	Image string `json:"image,omitempty"`
+	// Command overrides the image ENTRYPOINT
+	Command []string `json:"command,omitempty"`
+	// Args are passed to the command before the rendered tool arguments
+	Args []string `json:"args,omitempty"`
+	// Env is the list of environment variables set for each run
+	Env []corev1.EnvVar `json:"env,omitempty"`
+	// EnvFrom imports all keys of Secrets or ConfigMaps as environment variables
+	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
+	// Resources are the compute resources of the tool container
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// NodeSelector restricts the nodes the tool pod can be scheduled on
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations allow the tool pod to run on tainted nodes
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// Affinity holds the pod affinity and anti-affinity rules
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// PriorityClassName is the PriorityClass of the tool pod
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// RuntimeClassName is the RuntimeClass used to run the tool pod
+	RuntimeClassName *string `json:"runtimeClassName,omitempty"`
+
+	// ServiceAccountName is the service account the tool pod runs as
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+	// AutomountServiceAccountToken controls whether the service account token is mounted
+	AutomountServiceAccountToken *bool `json:"automountServiceAccountToken,omitempty"`
+	// SecurityProfile fills in secure defaults wherever the security contexts leave fields unset
+	SecurityProfile SecurityProfile `json:"securityProfile,omitempty"`
+	// PodSecurityContext holds the security attributes of the tool pod
+	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+	// SecurityContext holds the security attributes of the tool container
+	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
+
+	// Volumes are made available to the tool pod
+	Volumes []corev1.Volume `json:"volumes,omitempty"`
+	// VolumeMounts mount volumes into the tool container
+	VolumeMounts []corev1.VolumeMount `json:"volumeMounts,omitempty"`
+
+	// InitContainers run to completion before the tool container starts
+	InitContainers []corev1.Container `json:"initContainers,omitempty"`
+	// Sidecars run next to the tool container for the whole run
+	Sidecars []corev1.Container `json:"sidecars,omitempty"`
+
+	// BackoffLimit is the number of retries before the job is marked as failed
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+	// ActiveDeadlineSeconds limits the duration of a run
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+	// TTLSecondsAfterFinished is how long a finished job is kept before deletion
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+	// PodFailurePolicy decides whether a failed pod is retried or fails the job
+	PodFailurePolicy *batchv1.PodFailurePolicy `json:"podFailurePolicy,omitempty"`
+}
+
+// ToolStatus summarizes the runs of a tool. It is updated by rapt run when a run finishes,
+// and the argument count also whenever rapt writes the tool.
+type ToolStatus struct {
+	// LastRunTime is when the last finished run started
+	LastRunTime *metav1.Time `json:"lastRunTime,omitempty"`
+	// LastRunResult is the result of the last finished run
+	LastRunResult RunResult `json:"lastRunResult,omitempty"`
+	// LastJobName is the name of the job of the last finished run
+	LastJobName string `json:"lastJobName,omitempty"`
+	// SucceededRuns is the number of runs that succeeded
+	SucceededRuns int64 `json:"succeededRuns,omitempty"`
+	// FailedRuns is the number of runs that failed
+	FailedRuns int64 `json:"failedRuns,omitempty"`
+	// ArgumentCount is the number of arguments declared by the tool itself,
+	// not counting those inherited from the tools it extends
+	ArgumentCount *int32 `json:"argumentCount,omitempty"`
+}
+
+// RunResult is the result of a finished run
+type RunResult string
+
+const (
+	// RunSucceeded means that the job of the run completed successfully
+	RunSucceeded RunResult = "Succeeded"
+	// RunFailed means that the job of the run failed
+	RunFailed RunResult = "Failed"
+)
+
+// SecurityProfile names a set of security context defaults
+type SecurityProfile string
+
+const (
+	// SecurityProfileRestricted satisfies the restricted Pod Security Standard
+	SecurityProfileRestricted SecurityProfile = "Restricted"
+)
+
+// +kubebuilder:object:root=true
+
+// ToolList is a list of Tool objects
+type ToolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []Tool `json:"items"`
+}
+
+// NewTool returns an empty Tool with its type metadata set
+func NewTool(namespace, name string) *Tool {
+	return &Tool{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: GroupVersion.String(),
+			Kind:       ToolKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+}
@@ -0,0 +1,105 @@
+package v1alpha1
+
+import (
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ToolRevisionKind is the kind of the ToolRevision resource
+const ToolRevisionKind = "ToolRevision"
+
+// revisionUIDLength is the number of characters of the tool UID in the name of a revision
+const revisionUIDLength = 8
+
+const (
+	// ToolLabel is set on ToolRevisions and Jobs to the name of their tool
+	ToolLabel = "rapt.dev/tool"
+	// RevisionLabel is set on ToolRevisions and Jobs to the revision number of their tool
+	RevisionLabel = "rapt.dev/revision"
+	// RevisionAnnotation is set on a Tool to the number of its current revision
+	RevisionAnnotation = "rapt.dev/revision"
+	// ChangeCauseAnnotation is set on a ToolRevision to describe the change that created it
+	ChangeCauseAnnotation = "rapt.dev/change-cause"
+)
+
+// +kubebuilder:object:root=true
+
+// ToolRevision is an immutable snapshot of the spec of a Tool.
+// It is owned by the Tool and removed together with it.
+type ToolRevision struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Revision is the number of the revision, starting at 1
+	Revision int64 `json:"revision"`
+	// Spec is the spec of the tool at this revision
+	Spec ToolSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// ToolRevisionList is a list of ToolRevision objects
+type ToolRevisionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ToolRevision `json:"items"`
+}
+
+// NewToolRevision returns a revision of the tool's current spec, owned by the tool
+func NewToolRevision(tool *Tool, revision int64, changeCause string) *ToolRevision {
+	number := strconv.FormatInt(revision, 10)
+	toolRevision := &ToolRevision{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: GroupVersion.String(),
+			Kind:       ToolRevisionKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      revisionName(tool, number),
+			Namespace: tool.Namespace,
+			Labels: map[string]string{
+				ToolLabel:     tool.Name,
+				RevisionLabel: number,
+			},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: GroupVersion.String(),
+				Kind:       ToolKind,
+				Name:       tool.Name,
+				UID:        tool.UID,
+			}},
+		},
+		Revision: revision,
+		Spec:     *tool.Spec.DeepCopy(),
+	}
+	if changeCause != "" {
+		toolRevision.Annotations = map[string]string{ChangeCauseAnnotation: changeCause}
+	}
+	return toolRevision
+}
+
+// revisionName returns the name of a revision of the tool, <tool>-<uid>-<revision>.
+// The start of the tool UID tells revisions apart from those left over from a deleted tool
+// of the same name, which the garbage collector may not have removed yet.
+func revisionName(tool *Tool, number string) string {
+	suffix := "-" + number
+	if uid := string(tool.UID); uid != "" {
+		suffix = "-" + uid[:min(len(uid), revisionUIDLength)] + suffix
+	}
+	name := tool.Name
+	if len(name)+len(suffix) > validation.DNS1123SubdomainMaxLength {
+		name = strings.TrimRight(name[:validation.DNS1123SubdomainMaxLength-len(suffix)], "-.")
+	}
+	return name + suffix
+}
+
+// CurrentRevision returns the revision number recorded on the tool, or 0 if there is none
+func (t *Tool) CurrentRevision() int64 {
+	revision, err := strconv.ParseInt(t.Annotations[RevisionAnnotation], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return revision
+}
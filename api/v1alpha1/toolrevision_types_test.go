@@ -0,0 +1,45 @@
+package v1alpha1
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+func TestNewToolRevisionName(t *testing.T) {
+	tests := []struct {
+		name     string
+		toolName string
+		uid      types.UID
+		revision int64
+		want     string
+	}{
+		{name: "with uid", toolName: "report", uid: "3f9c2a1e-5b7d-4c1a-9e2f-0a1b2c3d4e5f", revision: 2, want: "report-3f9c2a1e-2"},
+		{name: "without uid", toolName: "report", revision: 1, want: "report-1"},
+		{name: "short uid", toolName: "report", uid: "abc", revision: 1, want: "report-abc-1"},
+		{name: "long name", toolName: strings.Repeat("a", 250), uid: "3f9c2a1e-5b7d", revision: 10, want: strings.Repeat("a", 241) + "-3f9c2a1e-10"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := &Tool{ObjectMeta: metav1.ObjectMeta{Name: tt.toolName, UID: tt.uid}}
+			got := NewToolRevision(tool, tt.revision, "").Name
+			if got != tt.want {
+				t.Errorf("name = %q, want %q", got, tt.want)
+			}
+			if errs := validation.IsDNS1123Subdomain(got); len(errs) > 0 {
+				t.Errorf("name %q is invalid: %v", got, errs)
+			}
+		})
+	}
+}
+
+func TestNewToolRevisionNamesDifferPerOwner(t *testing.T) {
+	deleted := &Tool{ObjectMeta: metav1.ObjectMeta{Name: "report", UID: "11111111-aaaa"}}
+	recreated := &Tool{ObjectMeta: metav1.ObjectMeta{Name: "report", UID: "22222222-bbbb"}}
+	if a, b := NewToolRevision(deleted, 1, "").Name, NewToolRevision(recreated, 1, "").Name; a == b {
+		t.Errorf("revision 1 of both tools is named %q", a)
+	}
+}
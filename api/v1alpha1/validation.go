@@ -0,0 +1,236 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ReservedVolumePrefix is reserved for volumes Rapt adds to a run, such as --mount files
+const ReservedVolumePrefix = "rapt-"
+
+// Validate checks the fields the CRD schema marks as required
+// and the references between fields that the schema cannot express.
+// A tool that extends another one may leave out fields and refer to volumes of its base;
+// those are checked once the tool is merged with its base.
+func (t *Tool) Validate() error {
+	if t.Spec.Extends != nil {
+		if err := t.validateExtends(); err != nil {
+			return fmt.Errorf("invalid tool %s: %w", t.Name, err)
+		}
+	} else if t.Spec.JobTemplate.Image == "" {
+		return fmt.Errorf("invalid tool %s: spec.jobTemplate.image is required", t.Name)
+	}
+	argumentNames := make(map[string]bool, len(t.Spec.Arguments))
+	for i, arg := range t.Spec.Arguments {
+		if arg.Name == "" {
+			return fmt.Errorf("invalid tool %s: spec.arguments[%d].name is required", t.Name, i)
+		}
+		if argumentNames[arg.Name] {
+			return fmt.Errorf("invalid tool %s: spec.arguments[%d]: duplicate argument name %q", t.Name, i, arg.Name)
+		}
+		argumentNames[arg.Name] = true
+		if err := arg.validateDefinition(); err != nil {
+			return fmt.Errorf("invalid tool %s: spec.arguments[%d] (%s): %w", t.Name, i, arg.Name, err)
+		}
+	}
+	envNames := make(map[string]bool, len(t.Spec.JobTemplate.Env))
+	for i, env := range t.Spec.JobTemplate.Env {
+		if env.Name == "" {
+			return fmt.Errorf("invalid tool %s: spec.jobTemplate.env[%d].name is required", t.Name, i)
+		}
+		if envNames[env.Name] {
+			return fmt.Errorf("invalid tool %s: spec.jobTemplate.env[%d]: duplicate environment variable name %q", t.Name, i, env.Name)
+		}
+		envNames[env.Name] = true
+		if env.Value != "" && env.ValueFrom != nil {
+			return fmt.Errorf("invalid tool %s: spec.jobTemplate.env[%d] sets both value and valueFrom", t.Name, i)
+		}
+	}
+
+	if err := t.validateContainers(); err != nil {
+		return fmt.Errorf("invalid tool %s: %w", t.Name, err)
+	}
+	if t.Spec.Extends == nil {
+		if err := t.validateVolumes(); err != nil {
+			return fmt.Errorf("invalid tool %s: %w", t.Name, err)
+		}
+	}
+	return nil
+}
+
+// ParsePlaceholders parses the {{ .argument }} placeholders of a command or args element.
+// Placeholders are opt-in: an element is only a template when it parses and every field it
+// refers to is a declared argument. Other elements, such as the go-template={{.metadata.name}}
+// of kubectl, are used verbatim and nil is returned for them.
+func (s *ToolSpec) ParsePlaceholders(text string) *template.Template {
+	tmpl, names := parsePlaceholders(text)
+	if len(names) == 0 {
+		return nil
+	}
+	for _, name := range names {
+		if !slices.ContainsFunc(s.Arguments, func(arg Argument) bool { return arg.Name == name }) {
+			return nil
+		}
+	}
+	return tmpl
+}
+
+// PlaceholderNames returns the names the {{ }} placeholders of a command or args element refer to,
+// whether they are declared arguments or not. It returns nil for elements that are not templates.
+func PlaceholderNames(text string) []string {
+	_, names := parsePlaceholders(text)
+	return names
+}
+
+// parsePlaceholders parses an element as a template and returns the names its fields refer to
+func parsePlaceholders(text string) (*template.Template, []string) {
+	if !strings.Contains(text, "{{") {
+		return nil, nil
+	}
+	tmpl, err := template.New("arg").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, nil
+	}
+	return tmpl, placeholderNames(tmpl.Tree.Root)
+}
+
+// placeholderNames returns the argument names referred to by the fields of a placeholder parse tree,
+// e.g. "name" for {{ .name }}, {{ $.name }}, {{ index . "name" }} or {{ if .name }}
+func placeholderNames(node parse.Node) []string {
+	var names []string
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			names = append(names, placeholderNames(child)...)
+		}
+	case *parse.ActionNode:
+		names = placeholderNames(n.Pipe)
+	case *parse.PipeNode:
+		if n == nil {
+			return nil
+		}
+		for _, cmd := range n.Cmds {
+			names = append(names, placeholderNames(cmd)...)
+		}
+	case *parse.CommandNode:
+		if len(n.Args) == 3 && n.Args[0].String() == "index" && n.Args[1].Type() == parse.NodeDot {
+			if key, ok := n.Args[2].(*parse.StringNode); ok {
+				names = append(names, key.Text)
+			}
+		}
+		for _, arg := range n.Args {
+			names = append(names, placeholderNames(arg)...)
+		}
+	case *parse.FieldNode:
+		names = append(names, n.Ident[0])
+	case *parse.VariableNode:
+		if len(n.Ident) > 1 && n.Ident[0] == "$" {
+			names = append(names, n.Ident[1])
+		}
+	case *parse.ChainNode:
+		names = placeholderNames(n.Node)
+	case *parse.IfNode:
+		names = branchPlaceholderNames(&n.BranchNode)
+	case *parse.RangeNode:
+		names = branchPlaceholderNames(&n.BranchNode)
+	case *parse.WithNode:
+		names = branchPlaceholderNames(&n.BranchNode)
+	}
+	return names
+}
+
+// branchPlaceholderNames returns the argument names referred to by a conditional and its branches
+func branchPlaceholderNames(n *parse.BranchNode) []string {
+	names := placeholderNames(n.Pipe)
+	names = append(names, placeholderNames(n.List)...)
+	return append(names, placeholderNames(n.ElseList)...)
+}
+
+// validateContainers checks that init containers and sidecars are complete and uniquely named
+func (t *Tool) validateContainers() error {
+	containerNames := map[string]bool{ToolContainerName: true}
+	for _, group := range []struct {
+		field      string
+		containers []corev1.Container
+	}{
+		{"initContainers", t.Spec.JobTemplate.InitContainers},
+		{"sidecars", t.Spec.JobTemplate.Sidecars},
+	} {
+		for i, container := range group.containers {
+			if container.Name == "" || container.Image == "" {
+				return fmt.Errorf("spec.jobTemplate.%s[%d] requires name and image", group.field, i)
+			}
+			if containerNames[container.Name] {
+				return fmt.Errorf("spec.jobTemplate.%s[%d]: container name %q is already in use", group.field, i, container.Name)
+			}
+			containerNames[container.Name] = true
+		}
+	}
+	return nil
+}
+
+// validateVolumes checks that volumes are uniquely named and that every mount refers to a declared volume
+func (t *Tool) validateVolumes() error {
+	jobTemplate := t.Spec.JobTemplate
+
+	volumeNames := make(map[string]bool)
+	for i, volume := range jobTemplate.Volumes {
+		if volume.Name == "" {
+			return fmt.Errorf("spec.jobTemplate.volumes[%d].name is required", i)
+		}
+		if strings.HasPrefix(volume.Name, ReservedVolumePrefix) {
+			return fmt.Errorf("spec.jobTemplate.volumes[%d]: volume names starting with %q are reserved", i, ReservedVolumePrefix)
+		}
+		if volumeNames[volume.Name] {
+			return fmt.Errorf("spec.jobTemplate.volumes[%d]: duplicate volume name %q", i, volume.Name)
+		}
+		volumeNames[volume.Name] = true
+	}
+
+	if err := validateVolumeMounts("spec.jobTemplate.volumeMounts", jobTemplate.VolumeMounts, volumeNames); err != nil {
+		return err
+	}
+	for _, group := range []struct {
+		field      string
+		containers []corev1.Container
+	}{
+		{"initContainers", jobTemplate.InitContainers},
+		{"sidecars", jobTemplate.Sidecars},
+	} {
+		for i, container := range group.containers {
+			field := fmt.Sprintf("spec.jobTemplate.%s[%d].volumeMounts", group.field, i)
+			if err := validateVolumeMounts(field, container.VolumeMounts, volumeNames); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateVolumeMounts checks the volume mounts of a single container
+func validateVolumeMounts(field string, mounts []corev1.VolumeMount, volumeNames map[string]bool) error {
+	mountPaths := make(map[string]bool)
+	for i, mount := range mounts {
+		if !volumeNames[mount.Name] {
+			return fmt.Errorf("%s[%d]: volume %q is not declared in spec.jobTemplate.volumes", field, i, mount.Name)
+		}
+		if !path.IsAbs(mount.MountPath) {
+			return fmt.Errorf("%s[%d]: mountPath %q must be an absolute path", field, i, mount.MountPath)
+		}
+		mountPath := path.Clean(mount.MountPath)
+		if mountPaths[mountPath] {
+			return fmt.Errorf("%s[%d]: mountPath %q is mounted more than once", field, i, mount.MountPath)
+		}
+		mountPaths[mountPath] = true
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package v1alpha1
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParsePlaceholders(t *testing.T) {
+	spec := &ToolSpec{Arguments: []Argument{
+		{Name: "input"},
+		{Name: "input-file"},
+		{Name: "verbose", Type: ArgumentTypeBool},
+	}}
+
+	tests := []struct {
+		name      string
+		text      string
+		templated bool
+		names     []string
+	}{
+		{name: "no placeholders", text: "--verbose"},
+		{name: "single braces", text: "{ .input }"},
+		{name: "declared argument", text: "--input={{ .input }}", templated: true, names: []string{"input"}},
+		{name: "index", text: `{{ index . "input-file" }}`, templated: true, names: []string{"input-file"}},
+		{name: "root variable", text: "{{ $.input }}", templated: true, names: []string{"input"}},
+		{name: "conditional", text: "{{ if .verbose }}-v{{ else }}{{ .input }}{{ end }}", templated: true, names: []string{"verbose", "input"}},
+		{name: "pipeline", text: `{{ .input | printf "%q" }}`, templated: true, names: []string{"input"}},
+		{name: "kubectl go-template", text: "go-template={{.metadata.name}}", names: []string{"metadata"}},
+		{name: "docker inspect format", text: "{{.State.Running}}", names: []string{"State"}},
+		{name: "declared and undeclared", text: "{{ .input }}-{{ .nope }}", names: []string{"input", "nope"}},
+		{name: "undeclared index", text: `{{ index . "output" }}`, names: []string{"output"}},
+		{name: "undeclared in else branch", text: "{{ if .verbose }}-v{{ else }}{{ .nope }}{{ end }}", names: []string{"verbose", "nope"}},
+		{name: "no fields", text: `{{ "literal" }}`},
+		{name: "syntax error", text: "{{ .input "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := spec.ParsePlaceholders(tt.text); (got != nil) != tt.templated {
+				t.Errorf("ParsePlaceholders(%q) = %v, want templated %v", tt.text, got, tt.templated)
+			}
+			if got := PlaceholderNames(tt.text); !slices.Equal(got, tt.names) {
+				t.Errorf("PlaceholderNames(%q) = %q, want %q", tt.text, got, tt.names)
+			}
+		})
+	}
+}
+
+func TestValidateVerbatimTemplates(t *testing.T) {
+	tool := &Tool{Spec: ToolSpec{
+		Arguments: []Argument{{Name: "selector"}},
+		JobTemplate: JobTemplate{
+			Image:   "bitnami/kubectl:1.31",
+			Command: []string{"kubectl", "get", "pods", "-o", "go-template={{range .items}}{{.metadata.name}}{{end}}"},
+			Args:    []string{"-l", "{{ .selector }}", "{{ .broken"},
+		},
+	}}
+	tool.Name = "pods"
+	if err := tool.Validate(); err != nil {
+		t.Errorf("Validate() error = %v, want nil", err)
+	}
+}
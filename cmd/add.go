@@ -31,10 +31,10 @@ import (
 
 // Add command flags
 var (
-	addImage   string
-	addCommand string
-	addEnv     []string
-	addDryRun  bool
+	addOptions     rapt.ToolOptions
+	addCluster     bool
+	addDryRun      bool
+	addInteractive bool
 )
 
 // addCmd represents the add command
@@ -44,30 +44,78 @@ var addCmd = &cobra.Command{
 	Long: `Add a tool (containerized job/command) to the Rapt system in your Kubernetes cluster.
 
 This command registers a new tool by specifying its container image, the command to run inside the image, and (optionally) a set of environment variables.
+The command is split like a shell would split it, so quotes keep words together; use --command-arg to give
+arguments exactly as they are, or --shell to run the command as a script with sh -c.
+Environment variables can also reference keys of Secrets and ConfigMaps, or import all keys of a Secret or ConfigMap.
+With --cluster the tool is created as a ClusterTool, which is available in every namespace.
+With --interactive the settings, including arguments and help text, are asked for one by one;
+the resulting YAML is shown and the tool is only created after confirmation.
 
 Examples:
   rapt add lstool -i alpine --command "ls -la"
-  rapt add echo --image busybox -e FOO=bar -e BAZ=qux --command "echo $FOO $BAZ"
+  rapt add echo --image busybox -e FOO=bar -e BAZ=qux --command 'echo $FOO $BAZ' --shell
+  rapt add greet --image busybox --command "sh -c 'echo hello world'"
+  rapt add jq --image ghcr.io/jqlang/jq --command jq --command-arg '.items[] | .name'
+  rapt add report --image python:3.12 --command "python report.py" --cpu 500m --memory 256Mi --memory-limit 512Mi
+  rapt add backup --image postgres:15-alpine --command pg_dump --env-secret PGPASSWORD=db-backup-secret:password --env-from-configmap backup-settings
+  rapt add curl --image curlimages/curl --command curl --cluster
+  rapt add --interactive
 `,
-	Args: cobra.ExactArgs(1),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if addInteractive {
+			return cobra.MaximumNArgs(1)(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		for _, e := range addEnv {
-			parts := strings.SplitN(e, "=", 2)
-			if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
-				return errors.New("each --env/-e argument must be in NAME=VALUE format")
+		if err := checkEnvFlags(addOptions.Env); err != nil {
+			return err
+		}
+		if addInteractive {
+			toolName := ""
+			if len(args) > 0 {
+				toolName = args[0]
 			}
+			return rapt.AddInteractive(namespace, toolName, addOptions, addCluster, addDryRun)
+		}
+		if addOptions.Image == "" {
+			return errors.New(`required flag(s) "image" not set`)
 		}
 		toolName := args[0]
-		return rapt.Add(namespace, toolName, addImage, addCommand, addEnv, addDryRun)
+		return rapt.Add(namespace, toolName, addOptions, addCluster, addDryRun)
 	},
 }
 
+// checkEnvFlags checks that every --env value is in NAME=VALUE format
+func checkEnvFlags(env []string) error {
+	for _, e := range env {
+		parts := strings.SplitN(e, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return errors.New("each --env/-e argument must be in NAME=VALUE format")
+		}
+	}
+	return nil
+}
+
 func init() {
 	rootCmd.AddCommand(addCmd)
 
-	addCmd.Flags().StringVarP(&addImage, "image", "i", "", "(Required) Container image to run.")
-	addCmd.MarkFlagRequired("image")
-	addCmd.Flags().StringVarP(&addCommand, "command", "c", "", "Command to run (overrides ENTRYPOINT). Specify as a single string.")
-	addCmd.Flags().StringArrayVarP(&addEnv, "env", "e", nil, "Environment variable in the form NAME=VALUE. Can be specified multiple times.")
+	addCmd.Flags().StringVarP(&addOptions.Image, "image", "i", "", "(Required unless --interactive) Container image to run.")
+	addCmd.Flags().StringVarP(&addOptions.Command, "command", "c", "", "Command to run (overrides ENTRYPOINT). Specify as a single string, split with shell quoting rules.")
+	addCmd.Flags().StringArrayVar(&addOptions.CommandArgs, "command-arg", nil, "Argument appended to the command exactly as given. Can be specified multiple times.")
+	addCmd.Flags().BoolVar(&addOptions.Shell, "shell", false, "Run --command as a shell script with sh -c; --command-arg values and tool arguments become $1, $2, ...")
+	addCmd.Flags().StringArrayVarP(&addOptions.Env, "env", "e", nil, "Environment variable in the form NAME=VALUE. Can be specified multiple times.")
+	addCmd.Flags().StringArrayVar(&addOptions.SecretEnv, "env-secret", nil, "Environment variable read from a Secret key in the form NAME=SECRET:KEY. Can be specified multiple times.")
+	addCmd.Flags().StringArrayVar(&addOptions.ConfigMapEnv, "env-configmap", nil, "Environment variable read from a ConfigMap key in the form NAME=CONFIGMAP:KEY. Can be specified multiple times.")
+	addCmd.Flags().StringArrayVar(&addOptions.EnvFromSecrets, "env-from-secret", nil, "Import all keys of a Secret as environment variables. Can be specified multiple times.")
+	addCmd.Flags().StringArrayVar(&addOptions.EnvFromConfigMaps, "env-from-configmap", nil, "Import all keys of a ConfigMap as environment variables. Can be specified multiple times.")
+	addCmd.Flags().StringVar(&addOptions.Resources.CPU, "cpu", "", "CPU request of the tool container, e.g. 500m")
+	addCmd.Flags().StringVar(&addOptions.Resources.Memory, "memory", "", "Memory request of the tool container, e.g. 256Mi")
+	addCmd.Flags().StringVar(&addOptions.Resources.CPULimit, "cpu-limit", "", "CPU limit of the tool container")
+	addCmd.Flags().StringVar(&addOptions.Resources.MemoryLimit, "memory-limit", "", "Memory limit of the tool container")
+	addCmd.Flags().StringVar(&addOptions.ServiceAccount, "service-account", "", "Service account the tool pod runs as")
+	addCmd.Flags().BoolVar(&addOptions.Restricted, "restricted", false, "Run the tool as non-root with a read-only root filesystem and no capabilities unless its security context says otherwise")
+	addCmd.Flags().BoolVar(&addCluster, "cluster", false, "Create a cluster-scoped ClusterTool that is available in every namespace")
 	addCmd.Flags().BoolVar(&addDryRun, "dry-run", false, "Print the Tool CR YAML without applying it to the cluster")
+	addCmd.Flags().BoolVar(&addInteractive, "interactive", false, "Ask for the tool settings, arguments and help text interactively and confirm before creating the tool")
 }
@@ -0,0 +1,71 @@
+/*
+Copyright © 2025 Serge Matveenko <lig@countzero.co>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"codeberg.org/lig/rapt/internal/app/rapt"
+	"github.com/spf13/cobra"
+)
+
+var (
+	applyFiles  []string
+	applyDryRun string
+)
+
+// applyCmd represents the apply command
+var applyCmd = &cobra.Command{
+	Use:   "apply -f <file|directory|->",
+	Short: "Create or update tools from YAML or JSON manifests",
+	Long: `Create or update Tools and ClusterTools from YAML or JSON manifests with server-side apply.
+
+A manifest path is a file, a directory whose .yaml, .yml and .json files are read,
+or - for stdin. A file may contain several documents separated by ---.
+
+Every document is checked against the Tool CRD schema and the rules rapt applies
+to tools before anything is sent to the cluster. If any document is invalid, all
+problems are reported and nothing is applied. Tools without a namespace are
+created in the current namespace.
+
+Each change to a namespaced Tool is recorded as a revision, see 'rapt history'.
+
+With --dry-run=client the manifests are only compared with the tools in the
+cluster; with --dry-run=server the API server processes the apply without
+storing the result.
+
+Examples:
+  rapt apply -f examples/file-processor.yaml
+  rapt apply -f examples/
+  rapt apply -f tools.yaml --dry-run=server
+  cat tool.yaml | rapt apply -f -`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return rapt.Apply(namespace, applyFiles, applyDryRun)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(applyCmd)
+
+	applyCmd.Flags().StringArrayVarP(&applyFiles, "filename", "f", nil, "File, directory or - for stdin with the manifests to apply. Can be specified multiple times.")
+	applyCmd.MarkFlagRequired("filename")
+	applyCmd.Flags().StringVar(&applyDryRun, "dry-run", rapt.DryRunNone, "Only report what would change: none, client or server")
+}
@@ -29,8 +29,9 @@ import (
 )
 
 var (
-	deleteForce bool
-	deleteAll   bool
+	deleteForce   bool
+	deleteAll     bool
+	deleteCluster bool
 )
 
 // deleteCmd represents the delete command
@@ -41,13 +42,18 @@ var deleteCmd = &cobra.Command{
 
 This command removes the specified tool definition, making it no longer available for execution.
 You can delete multiple tools at once or use --all to delete all tools in the namespace.
+Use --cluster to delete ClusterTools instead of tools in the namespace.
 
 Examples:
   rapt delete echo-tool
   rapt delete tool1 tool2 tool3
   rapt delete --all
-  rapt delete --all --force`,
+  rapt delete --all --force
+  rapt delete curl --cluster`,
 	Args: func(cmd *cobra.Command, args []string) error {
+		if deleteAll && deleteCluster {
+			return fmt.Errorf("cannot use --all with --cluster")
+		}
 		if deleteAll && len(args) > 0 {
 			return fmt.Errorf("cannot specify tool names when using --all")
 		}
@@ -60,7 +66,7 @@ Examples:
 		if deleteAll {
 			return rapt.DeleteAllTools(namespace, deleteForce)
 		}
-		return rapt.DeleteTools(namespace, args, deleteForce)
+		return rapt.DeleteTools(namespace, args, deleteCluster, deleteForce)
 	},
 }
 
@@ -69,4 +75,5 @@ func init() {
 
 	deleteCmd.Flags().BoolVarP(&deleteForce, "force", "f", false, "Skip confirmation prompt")
 	deleteCmd.Flags().BoolVarP(&deleteAll, "all", "a", false, "Delete all tools in the namespace")
+	deleteCmd.Flags().BoolVar(&deleteCluster, "cluster", false, "Delete cluster-scoped ClusterTools")
 }
\ No newline at end of file
@@ -27,7 +27,9 @@ import (
 )
 
 var (
-	describeOutput string
+	describeOutput   string
+	describeArgs     []string
+	describeResolved bool
 )
 
 // describeCmd represents the describe command
@@ -39,14 +41,26 @@ var describeCmd = &cobra.Command{
 This command displays comprehensive information about a tool including its
 configuration, arguments, environment variables, and metadata.
 
+It also shows the command line the tool runs with. Pass --arg to see the command
+line for a given set of arguments; missing required arguments are shown as <name>.
+
+For a tool that extends another tool, --resolved shows the effective spec merged
+with the whole extends chain instead of the fields the tool sets itself.
+
 Examples:
   rapt describe echo-tool
   rapt describe db-migrate --output json
-  rapt describe my-tool --output yaml`,
+  rapt describe my-tool --output yaml
+  rapt describe file-processor --arg operation=compress --arg input-file=/data/in.txt
+  rapt describe staging-backup --resolved`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		toolName := args[0]
-		return rapt.DescribeTool(namespace, toolName, describeOutput)
+		argMap, err := parseArgs(describeArgs)
+		if err != nil {
+			return err
+		}
+		return rapt.DescribeTool(namespace, toolName, describeOutput, argMap, describeResolved)
 	},
 }
 
@@ -54,4 +68,6 @@ func init() {
 	rootCmd.AddCommand(describeCmd)
 
 	describeCmd.Flags().StringVarP(&describeOutput, "output", "o", "table", "Output format: table, json, yaml")
+	describeCmd.Flags().BoolVar(&describeResolved, "resolved", false, "Show the effective spec merged with the tools it extends")
+	describeCmd.Flags().StringArrayVarP(&describeArgs, "arg", "a", nil, "Tool argument in the form key=value used to render the command line. Can be specified multiple times.")
 }
\ No newline at end of file
@@ -0,0 +1,54 @@
+/*
+Copyright © 2025 Serge Matveenko <lig@countzero.co>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"codeberg.org/lig/rapt/internal/app/rapt"
+	"github.com/spf13/cobra"
+)
+
+var diffRevision int64
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff <tool-name> --revision <number>",
+	Short: "Show how a tool differs from one of its revisions",
+	Long: `Show how the current spec of a tool differs from one of its revisions.
+
+The diff is shown in unified format, from the given revision to the current spec.
+Use 'rapt history' to list the revisions of a tool.
+
+Examples:
+  rapt diff echo-tool --revision 1
+  rapt diff db-migrate --revision 3 --namespace production`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return rapt.DiffRevision(namespace, args[0], diffRevision)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+
+	diffCmd.Flags().Int64Var(&diffRevision, "revision", 0, "Revision to compare the current spec with")
+	diffCmd.MarkFlagRequired("revision")
+}
@@ -0,0 +1,58 @@
+/*
+Copyright © 2025 Serge Matveenko <lig@countzero.co>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"codeberg.org/lig/rapt/internal/app/rapt"
+	"github.com/spf13/cobra"
+)
+
+var editCluster bool
+
+// editCmd represents the edit command
+var editCmd = &cobra.Command{
+	Use:   "edit <tool-name>",
+	Short: "Edit a tool definition in your editor",
+	Long: `Open the YAML of a tool in $EDITOR (vi if it is not set) and save the changes to the cluster.
+
+The edited tool is checked against the CRD schema and the tool validation before it is
+saved. If it is invalid, the editor is reopened with the problems listed at the top of
+the file; saving an empty file cancels the edit. The tool is only replaced if nobody else
+changed it in the meantime, otherwise the edit is kept in a temporary file.
+
+Every saved edit of a namespaced tool is recorded as a new revision, see 'rapt history'.
+
+Examples:
+  rapt edit echo-tool
+  EDITOR="code --wait" rapt edit db-migrate
+  rapt edit curl --cluster`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return rapt.EditTool(namespace, args[0], editCluster)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(editCmd)
+
+	editCmd.Flags().BoolVar(&editCluster, "cluster", false, "Edit a cluster-scoped ClusterTool")
+}
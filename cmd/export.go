@@ -0,0 +1,62 @@
+/*
+Copyright © 2025 Serge Matveenko <lig@countzero.co>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"codeberg.org/lig/rapt/internal/app/rapt"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportAll     bool
+	exportCluster bool
+	exportOutput  string
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export [tool-name...] [--all]",
+	Short: "Export tools as a portable YAML bundle",
+	Long: `Write tools as a multi-document YAML bundle that can be imported into another
+namespace or cluster with 'rapt import'.
+
+Fields that only make sense in the source cluster are left out: the namespace,
+uid, resourceVersion, managedFields, timestamps, the status and the revision
+annotation. Tools that extend a tool which is not part of the bundle are reported,
+since their base must exist where the bundle is imported.
+
+Examples:
+  rapt export echo-tool db-migrate > tools.yaml
+  rapt export --all --namespace staging -o staging-tools.yaml
+  rapt export --all --cluster -o cluster-tools.yaml`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return rapt.Export(namespace, args, exportAll, exportCluster, exportOutput)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().BoolVar(&exportAll, "all", false, "Export every tool of the namespace")
+	exportCmd.Flags().BoolVar(&exportCluster, "cluster", false, "Export cluster-scoped ClusterTools")
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "File to write the bundle to instead of stdout")
+}
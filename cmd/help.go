@@ -0,0 +1,64 @@
+/*
+Copyright © 2025 Serge Matveenko <lig@countzero.co>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"codeberg.org/lig/rapt/internal/app/rapt"
+)
+
+// helpCmd replaces cobra's help command so that it also explains tools
+var helpCmd = &cobra.Command{
+	Use:   "help [command | tool-name]",
+	Short: "Help about any command or tool",
+	Long: `Help provides help for any command in the application, or for a tool defined in the cluster.
+
+For a tool, it shows its help text, usage, arguments and example invocations
+generated from the Tool resource. Commands take precedence over tools with the same name.
+
+Examples:
+  rapt help run
+  rapt help file-processor
+  rapt help db-backup --namespace production`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		target, _, err := cmd.Root().Find(args)
+		if len(args) == 1 && (err != nil || target == cmd.Root()) {
+			tool, err := rapt.GetTool(namespace, args[0])
+			if err != nil {
+				return err
+			}
+			return rapt.ShowToolHelp(tool, reservedRunFlags(runCmd))
+		}
+		if target == nil || err != nil {
+			cmd.Printf("Unknown help topic %#q\n", args)
+			return cmd.Root().Usage()
+		}
+		target.InitDefaultHelpFlag()
+		target.InitDefaultVersionFlag()
+		return target.Help()
+	},
+}
+
+func init() {
+	rootCmd.SetHelpCommand(helpCmd)
+}
@@ -0,0 +1,72 @@
+/*
+Copyright © 2025 Serge Matveenko <lig@countzero.co>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"codeberg.org/lig/rapt/internal/app/rapt"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importFiles      []string
+	importOnConflict string
+	importDryRun     bool
+)
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import -f <bundle>",
+	Short: "Create tools from a bundle written by rapt export",
+	Long: `Create the Tools and ClusterTools of a bundle written by 'rapt export'.
+
+Tools are imported into the namespace given with --namespace, or the current one,
+whatever namespace the bundle names. Every tool is checked like a manifest of
+'rapt apply' before anything is created.
+
+--on-conflict decides what happens to tools that already exist and differ from
+the bundle:
+  fail       report them and import nothing (default)
+  skip       keep the existing tools and import the others
+  overwrite  replace the existing tools with those of the bundle
+
+A tool that already matches the bundle is no conflict and is reported as
+unchanged, whatever the policy. Imported and
+overwritten Tools are recorded as revisions, see 'rapt history'.
+
+Examples:
+  rapt export --all -n staging | rapt import -f - -n production
+  rapt import -f tools.yaml --namespace production --on-conflict overwrite
+  rapt import -f tools.yaml --on-conflict skip --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return rapt.Import(namespace, importFiles, importOnConflict, importDryRun)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+
+	importCmd.Flags().StringArrayVarP(&importFiles, "filename", "f", nil, "Bundle file, directory or - for stdin. Can be specified multiple times.")
+	importCmd.MarkFlagRequired("filename")
+	importCmd.Flags().StringVar(&importOnConflict, "on-conflict", rapt.ConflictFail, "What to do with tools that already exist: fail, skip or overwrite")
+	importCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "Only report what would be imported")
+}
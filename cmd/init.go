@@ -31,10 +31,13 @@ var initDryRun bool
 // initCmd represents the init command
 var initCmd = &cobra.Command{
 	Use:   "init",
-	Short: "Install the Rapt CRD in your Kubernetes cluster.",
-	Long: `Initialize your Kubernetes cluster for use with Rapt by installing the Rapt CustomResourceDefinition (CRD).
+	Short: "Install the Rapt CRDs in your Kubernetes cluster.",
+	Long: `Initialize your Kubernetes cluster for use with Rapt by installing the Rapt CustomResourceDefinitions (CRDs)
+for the namespaced Tool kind and the cluster-scoped ClusterTool kind.
 
-This command sets up the necessary CRD so that Rapt can manage and orchestrate predefined jobs (commands) in your cluster. Run this command once per cluster before using other Rapt features.`,
+This command sets up the necessary CRDs so that Rapt can manage and orchestrate predefined jobs (commands) in your cluster. Run this command once per cluster before using other Rapt features.
+
+If a CRD is already installed, it is upgraded to the schema shipped with this version of Rapt.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return rapt.InitCmd(namespace, initDryRun)
 	},
@@ -42,5 +45,5 @@ This command sets up the necessary CRD so that Rapt can manage and orchestrate p
 
 func init() {
 	rootCmd.AddCommand(initCmd)
-	initCmd.Flags().BoolVar(&initDryRun, "dry-run", false, "Print the CRD YAML without applying it to the cluster")
+	initCmd.Flags().BoolVar(&initDryRun, "dry-run", false, "Print the CRD YAML documents without applying it to the cluster")
 }
@@ -0,0 +1,68 @@
+/*
+Copyright © 2025 Serge Matveenko <lig@countzero.co>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"codeberg.org/lig/rapt/internal/app/rapt"
+	"github.com/spf13/cobra"
+)
+
+var (
+	lintFiles  []string
+	lintOutput string
+)
+
+// lintCmd represents the lint command
+var lintCmd = &cobra.Command{
+	Use:   "lint -f <file|directory|->",
+	Short: "Check tool manifests for problems and best practices without a cluster",
+	Long: `Check Tool and ClusterTool manifests like 'rapt validate' and also against the
+best practices for tools, without contacting a cluster:
+
+  image-latest-tag           images are pinned to a tag or digest other than latest
+  plaintext-secret           variables named like credentials (*_PASSWORD, *_TOKEN,
+                             *_API_KEY, ...) are read from Secrets, not set to plain values
+  duplicate-env-name         no environment variable is set twice in a container
+  required-argument-default  required arguments have no default value
+  verbatim-placeholder       placeholders that look meant for arguments refer to declared
+                             arguments, so that the element is not passed verbatim
+
+Every finding names its rule. The report is written in the format chosen with
+--output: text, json or sarif. The command fails if anything is found.
+
+Examples:
+  rapt lint -f examples/
+  rapt lint -f tools/ --output sarif > rapt.sarif`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return rapt.Lint(lintFiles, lintOutput)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+
+	lintCmd.Flags().StringArrayVarP(&lintFiles, "filename", "f", nil, "File, directory or - for stdin with the manifests to check. Can be specified multiple times.")
+	lintCmd.MarkFlagRequired("filename")
+	lintCmd.Flags().StringVarP(&lintOutput, "output", "o", rapt.FormatText, "Report format: text, json or sarif")
+}
@@ -22,13 +22,15 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"codeberg.org/lig/rapt/api/v1alpha1"
 	"codeberg.org/lig/rapt/internal/app/rapt"
 	"github.com/spf13/cobra"
 )
 
 var (
-	logsFollow bool
-	logsTail   int
+	logsFollow    bool
+	logsTail      int
+	logsContainer string
 )
 
 // logsCmd represents the logs command
@@ -45,7 +47,8 @@ Examples:
   rapt logs echo-tool                    # List previous runs for echo-tool
   rapt logs echo-tool echo-tool-20250115-143022  # Show logs for specific job
   rapt logs db-migrate --follow         # Follow logs for the latest job
-  rapt logs my-tool --tail 100          # Show last 100 lines of logs`,
+  rapt logs my-tool --tail 100          # Show last 100 lines of logs
+  rapt logs my-tool my-tool-20250115-143022 --container sql-proxy  # Show logs of a sidecar`,
 	Args: cobra.RangeArgs(1, 2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		toolName := args[0]
@@ -54,7 +57,7 @@ Examples:
 			jobName = args[1]
 		}
 		
-		return rapt.ShowLogs(namespace, toolName, jobName, logsFollow, logsTail)
+		return rapt.ShowLogs(namespace, toolName, jobName, logsContainer, logsFollow, logsTail)
 	},
 }
 
@@ -62,5 +65,6 @@ func init() {
 	rootCmd.AddCommand(logsCmd)
 
 	logsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "Follow logs in real-time (only for specific job)")
+	logsCmd.Flags().StringVarP(&logsContainer, "container", "c", v1alpha1.ToolContainerName, "Container to show logs for (init containers and sidecars included)")
 	logsCmd.Flags().IntVarP(&logsTail, "tail", "t", 0, "Number of lines to show from the end of logs (0 = all)")
 }
\ No newline at end of file
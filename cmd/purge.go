@@ -29,11 +29,11 @@ import (
 // purgeCmd represents the purge command
 var purgeCmd = &cobra.Command{
 	Use:   "purge",
-	Short: "Remove the Rapt CRD and all associated resources from your Kubernetes cluster.",
-	Long: `Purge all Rapt-related resources from your Kubernetes cluster, including the Rapt CustomResourceDefinition (CRD).
+	Short: "Remove the Rapt CRDs and all associated resources from your Kubernetes cluster.",
+	Long: `Purge all Rapt-related resources from your Kubernetes cluster, including the Rapt CustomResourceDefinitions (CRDs).
 
 This command is useful for cleanup purposes when you no longer wish to use Rapt in a given cluster. 
-It will remove the CRD and all associated custom resources managed by Rapt.
+It will remove the CRDs and all associated custom resources managed by Rapt, including ClusterTools and ToolRevisions.
 
 ⚠️ Warning: This operation is destructive and cannot be undone. Ensure you have backups if needed before proceeding.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -23,8 +23,10 @@ package cmd
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	
+	"codeberg.org/lig/rapt/api/v1alpha1"
 	"codeberg.org/lig/rapt/internal/app/rapt"
 	"github.com/spf13/cobra"
 )
@@ -35,7 +37,18 @@ var (
 	runMounts  []string
 	runWait    bool
 	runFollow  bool
+	runDetach  bool
+	runOutput  string
 	runTimeout int
+
+	runBackoffLimit   int32
+	runActiveDeadline int64
+	runTTL            int32
+	runFailOnExit     []int32
+	runResources      rapt.ResourceSpec
+	runNodeSelector   []string
+	runRestricted     bool
+	runContainer      string
 )
 
 // runCmd represents the run command
@@ -46,29 +59,97 @@ var runCmd = &cobra.Command{
 
 This command creates a Kubernetes Job that runs the specified tool with the given arguments and environment variables.
 Logs are streamed in real-time by default, making it feel like running a local command.
+With --wait the command blocks until the job finishes without showing its logs, and
+with --detach it prints the name of the job and exits as soon as the job is created.
+
+Every argument declared by the tool is also available as a flag, for example
+--input-file data.csv instead of --arg input-file=data.csv. Tool flags follow the
+tool name. Arguments named like one of the flags below can only be given with --arg,
+except for --detach and --output: an argument with one of these names keeps its flag,
+and the run flag is not available for that tool.
+Run "rapt help <tool-name>" or "rapt run <tool-name> --help" to see them.
 
 You can mount local files into the job container using the --mount flag with the format:
 local-path:container-path
 
 Examples:
   rapt run echo-tool --arg message="Hello World"
+  rapt run echo-tool --message "Hello World"
   rapt run db-migrate --arg database=production --arg script=migration.sql
   rapt run file-processor --env DEBUG=true
   rapt run my-tool --arg input=/tmp/data.json --arg output=result.txt --mount ./data.json:/tmp/data.json --mount ./config.yaml:/etc/config.yaml
   rapt run script-runner --mount ./script.sh:/app/script.sh --arg script=/app/script.sh --env DEBUG=true
-  rapt run data-processor --mount ./input.csv:/data/input.csv --mount ./schema.json:/app/schema.json --arg input=/data/input.csv --arg schema=/app/schema.json --arg format=json`,
-	Args: cobra.ExactArgs(1),
-	RunE: func(cmd *cobra.Command, args []string) error {
-		toolName := args[0]
-		
+  rapt run data-processor --mount ./input.csv:/data/input.csv --mount ./schema.json:/app/schema.json --arg input=/data/input.csv --arg schema=/app/schema.json --arg format=json
+  rapt run flaky-tool --backoff-limit 0 --active-deadline 600 --ttl 86400
+  rapt run db-migrate --fail-on-exit-code 2 --fail-on-exit-code 3
+  rapt run report --cpu 2 --memory 4Gi
+  rapt run batch-job --node-selector node-role/batch=true
+  rapt run data-processor --wait --timeout 3600
+  job=$(rapt run report --detach)`,
+	// Tool arguments become flags, so the tool has to be known before the flags are parsed
+	DisableFlagParsing: true,
+	RunE: func(cmd *cobra.Command, rawArgs []string) error {
+		toolName, toolNamespace, help := scanRunArgs(cmd, rawArgs)
+		if toolName == "" {
+			if help {
+				return cmd.Help()
+			}
+			return fmt.Errorf("accepts 1 arg(s), received 0")
+		}
+
+		tool, err := rapt.GetTool(toolNamespace, toolName)
+		if err != nil {
+			if help {
+				return cmd.Help()
+			}
+			return err
+		}
+
+		reserved := reservedRunFlags(cmd)
+		toolFlags := addToolFlags(cmd.Flags(), tool)
+		if err := cmd.Flags().Parse(rawArgs); err != nil {
+			return cmd.FlagErrorFunc()(cmd, err)
+		}
+		if help {
+			return rapt.ShowToolHelp(tool, reserved)
+		}
+		if args := cmd.Flags().Args(); len(args) != 1 {
+			return fmt.Errorf("accepts 1 arg(s), received %d", len(args))
+		}
+
+		// Decide how long to stay with the job
+		mode := rapt.RunFollow
+		switch {
+		case runDetach:
+			for _, name := range []string{"wait", "follow", "timeout", "container"} {
+				if cmd.Flags().Changed(name) {
+					return fmt.Errorf("--detach cannot be combined with --%s", name)
+				}
+			}
+			mode = rapt.RunDetach
+		case !runFollow && (runWait || cmd.Flags().Changed("follow")):
+			mode = rapt.RunWait
+		}
+		if _, isArgument := toolFlags["output"]; !isArgument && cmd.Flags().Changed("output") && !runDetach {
+			return fmt.Errorf("--output is only supported with --detach")
+		}
+		if runOutput != "name" && runOutput != "json" {
+			return fmt.Errorf("invalid --output value %q, must be name or json", runOutput)
+		}
+
 		// Parse arguments into key-value pairs
-		argMap := make(map[string]string)
-		for _, arg := range runArgs {
-			parts := splitKeyValue(arg, "=")
-			if len(parts) != 2 {
-				return fmt.Errorf("invalid argument format: %s (expected key=value)", arg)
+		argMap, err := parseArgs(runArgs)
+		if err != nil {
+			return err
+		}
+		for name, flag := range toolFlags {
+			if !flag.Changed {
+				continue
 			}
-			argMap[parts[0]] = parts[1]
+			if _, exists := argMap[name]; exists {
+				return fmt.Errorf("argument '%s' is given both as --%s and --arg", name, name)
+			}
+			argMap[name] = flag.Value.String()
 		}
 		
 		// Parse environment variables
@@ -94,7 +175,54 @@ Examples:
 			}
 		}
 		
-		return rapt.RunTool(namespace, toolName, argMap, envMap, mounts, runWait, runFollow, runTimeout)
+		// Parse job policy overrides
+		var overrides rapt.JobOverrides
+		if cmd.Flags().Changed("backoff-limit") {
+			if runBackoffLimit < 0 {
+				return fmt.Errorf("--backoff-limit must not be negative")
+			}
+			overrides.BackoffLimit = &runBackoffLimit
+		}
+		if cmd.Flags().Changed("active-deadline") {
+			if runActiveDeadline <= 0 {
+				return fmt.Errorf("--active-deadline must be positive")
+			}
+			overrides.ActiveDeadlineSeconds = &runActiveDeadline
+		}
+		if cmd.Flags().Changed("ttl") {
+			if runTTL < 0 {
+				return fmt.Errorf("--ttl must not be negative")
+			}
+			overrides.TTLSecondsAfterFinished = &runTTL
+		}
+		for _, code := range runFailOnExit {
+			if code == 0 {
+				return fmt.Errorf("--fail-on-exit-code cannot be 0")
+			}
+		}
+		if len(runFailOnExit) > 0 {
+			// Kubernetes requires exit codes to be sorted and unique
+			codes := slices.Clone(runFailOnExit)
+			slices.Sort(codes)
+			overrides.FailOnExitCodes = slices.Compact(codes)
+		}
+
+		overrides.Resources = runResources
+		overrides.Restricted = runRestricted
+
+		// Parse node selector overrides
+		for _, selector := range runNodeSelector {
+			parts := splitKeyValue(selector, "=")
+			if len(parts) != 2 || parts[0] == "" {
+				return fmt.Errorf("invalid node selector format: %s (expected key=value)", selector)
+			}
+			if overrides.NodeSelector == nil {
+				overrides.NodeSelector = make(map[string]string)
+			}
+			overrides.NodeSelector[parts[0]] = parts[1]
+		}
+
+		return rapt.RunTool(namespace, tool, argMap, envMap, mounts, overrides, runContainer, mode, runOutput, runTimeout)
 	},
 }
 
@@ -104,9 +232,35 @@ func init() {
 	runCmd.Flags().StringArrayVarP(&runArgs, "arg", "a", nil, "Tool argument in the form key=value. Can be specified multiple times.")
 	runCmd.Flags().StringArrayVarP(&runEnv, "env", "e", nil, "Environment variable in the form key=value. Can be specified multiple times.")
 	runCmd.Flags().StringArrayVarP(&runMounts, "mount", "m", nil, "Mount local file into container in the form local-path:container-path. Can be specified multiple times.")
-	runCmd.Flags().BoolVarP(&runWait, "wait", "w", false, "Wait for the job to complete before exiting (logs are always shown)")
-	runCmd.Flags().BoolVarP(&runFollow, "follow", "f", false, "Follow job logs in real-time (default behavior)")
+	runCmd.Flags().BoolVarP(&runWait, "wait", "w", false, "Wait for the job to complete without streaming its logs (combine with --follow to stream them)")
+	runCmd.Flags().BoolVarP(&runFollow, "follow", "f", false, "Stream the job logs until it completes (the default unless --wait is given)")
+	runCmd.Flags().BoolVarP(&runDetach, "detach", "d", false, "Print the name of the job and exit as soon as it is created")
+	runCmd.Flags().StringVarP(&runOutput, "output", "o", "name", "Output of --detach: name or json")
+	runCmd.Flags().StringVarP(&runContainer, "container", "c", v1alpha1.ToolContainerName, "Container whose logs are streamed (init containers and sidecars included)")
 	runCmd.Flags().IntVarP(&runTimeout, "timeout", "t", 300, "Timeout in seconds when waiting for job completion (0 = no timeout)")
+	runCmd.Flags().Int32Var(&runBackoffLimit, "backoff-limit", 0, "Number of retries before the job is marked as failed (overrides the tool definition)")
+	runCmd.Flags().Int64Var(&runActiveDeadline, "active-deadline", 0, "Maximum duration of the run in seconds (overrides the tool definition)")
+	runCmd.Flags().Int32Var(&runTTL, "ttl", 0, "Seconds to keep the finished job and its logs (overrides the tool definition)")
+	runCmd.Flags().StringVar(&runResources.CPU, "cpu", "", "CPU request for this run (overrides the tool definition)")
+	runCmd.Flags().StringVar(&runResources.Memory, "memory", "", "Memory request for this run (overrides the tool definition)")
+	runCmd.Flags().StringVar(&runResources.CPULimit, "cpu-limit", "", "CPU limit for this run (overrides the tool definition)")
+	runCmd.Flags().StringVar(&runResources.MemoryLimit, "memory-limit", "", "Memory limit for this run (overrides the tool definition)")
+	runCmd.Flags().StringArrayVar(&runNodeSelector, "node-selector", nil, "Node label the job pod must match in the form key=value (merged with the tool definition). Can be specified multiple times.")
+	runCmd.Flags().BoolVar(&runRestricted, "restricted", false, "Apply the restricted security defaults for this run (non-root, read-only root filesystem, no capabilities)")
+	runCmd.Flags().Int32SliceVar(&runFailOnExit, "fail-on-exit-code", nil, "Fail the job without retrying when the tool exits with this code. Can be specified multiple times.")
+}
+
+// parseArgs parses --arg values in the form key=value into a map
+func parseArgs(args []string) (map[string]string, error) {
+	argMap := make(map[string]string)
+	for _, arg := range args {
+		parts := splitKeyValue(arg, "=")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid argument format: %s (expected key=value)", arg)
+		}
+		argMap[parts[0]] = parts[1]
+	}
+	return argMap, nil
 }
 
 // splitKeyValue splits a string by the first occurrence of the separator
@@ -0,0 +1,149 @@
+/*
+Copyright © 2025 Serge Matveenko <lig@countzero.co>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"codeberg.org/lig/rapt/api/v1alpha1"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// toolFirstFlags are flags of `rapt run` that were added after tool arguments became flags.
+// A tool argument with one of these names keeps its flag, which then sets the argument.
+var toolFirstFlags = []string{"detach", "output"}
+
+// scanValue accepts any value, so that flags can be scanned without setting the real ones
+type scanValue struct {
+	value string
+	typ   string
+}
+
+func (v *scanValue) String() string { return v.value }
+
+func (v *scanValue) Set(value string) error {
+	v.value = value
+	return nil
+}
+
+func (v *scanValue) Type() string { return v.typ }
+
+// scanRunArgs finds the tool name, namespace and help flag in the raw `rapt run` arguments
+// without parsing the real flags. Flags the command does not know yet are ignored.
+func scanRunArgs(cmd *cobra.Command, args []string) (toolName, toolNamespace string, help bool) {
+	scratch := pflag.NewFlagSet(cmd.Name(), pflag.ContinueOnError)
+	scratch.ParseErrorsWhitelist.UnknownFlags = true
+	scratch.SetOutput(io.Discard)
+
+	// Make sure --help and inherited flags such as --namespace are part of the command's flag set
+	cmd.InitDefaultHelpFlag()
+	cmd.InheritedFlags()
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		scratch.AddFlag(&pflag.Flag{
+			Name:        flag.Name,
+			Shorthand:   flag.Shorthand,
+			NoOptDefVal: flag.NoOptDefVal,
+			Value:       &scanValue{value: flag.DefValue, typ: flag.Value.Type()},
+		})
+	})
+
+	// Errors are reported by the real parse
+	_ = scratch.Parse(args)
+
+	if scratch.NArg() > 0 {
+		toolName = scratch.Arg(0)
+	}
+	if flag := scratch.Lookup("namespace"); flag != nil {
+		toolNamespace = flag.Value.String()
+	}
+	if flag := scratch.Lookup("help"); flag != nil {
+		help = flag.Changed && flag.Value.String() != "false"
+	}
+	return toolName, toolNamespace, help
+}
+
+// reservedRunFlags returns the names of the flags of `rapt run`, which is passed as cmd.
+// Tool arguments with these names can only be given with --arg.
+func reservedRunFlags(cmd *cobra.Command) map[string]bool {
+	reserved := map[string]bool{"help": true}
+	cmd.InheritedFlags()
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if !slices.Contains(toolFirstFlags, flag.Name) {
+			reserved[flag.Name] = true
+		}
+	})
+	return reserved
+}
+
+// argumentValue holds a tool argument given as a flag. Values are validated with the
+// rest of the arguments, so any string is accepted here.
+type argumentValue struct {
+	value string
+	typ   v1alpha1.ArgumentType
+}
+
+func (v *argumentValue) String() string { return v.value }
+
+func (v *argumentValue) Set(value string) error {
+	v.value = value
+	return nil
+}
+
+func (v *argumentValue) Type() string { return string(v.typ) }
+
+// addToolFlags adds a flag for every argument of the tool that does not clash with an
+// existing flag, and returns the added flags by argument name. Arguments named like
+// one of the toolFirstFlags take that flag over.
+func addToolFlags(flags *pflag.FlagSet, tool *v1alpha1.Tool) map[string]*pflag.Flag {
+	toolFlags := make(map[string]*pflag.Flag)
+	for _, arg := range tool.Spec.Arguments {
+		existing := flags.Lookup(arg.Name)
+		if existing != nil && !slices.Contains(toolFirstFlags, arg.Name) {
+			continue
+		}
+
+		usage := arg.Description
+		if arg.Required {
+			usage = strings.TrimSpace(usage + " (required)")
+		}
+		if len(arg.Values) > 0 {
+			usage = strings.TrimSpace(fmt.Sprintf("%s (one of: %s)", usage, strings.Join(arg.Values, ", ")))
+		}
+
+		value := &argumentValue{value: arg.Default, typ: arg.ArgType()}
+		flag := existing
+		if flag == nil {
+			flag = flags.VarPF(value, arg.Name, "", usage)
+		} else {
+			flag.Value, flag.DefValue, flag.Usage, flag.NoOptDefVal = value, arg.Default, usage, ""
+		}
+		if arg.ArgType() == v1alpha1.ArgumentTypeBool {
+			flag.NoOptDefVal = "true"
+		}
+		toolFlags[arg.Name] = flag
+	}
+	return toolFlags
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"testing"
+
+	"codeberg.org/lig/rapt/api/v1alpha1"
+	"github.com/spf13/pflag"
+)
+
+func TestAddToolFlags(t *testing.T) {
+	var detach bool
+	var output, env string
+	flags := pflag.NewFlagSet("run", pflag.ContinueOnError)
+	flags.BoolVarP(&detach, "detach", "d", false, "")
+	flags.StringVarP(&output, "output", "o", "name", "")
+	flags.StringVarP(&env, "env", "e", "", "")
+
+	tool := &v1alpha1.Tool{Spec: v1alpha1.ToolSpec{Arguments: []v1alpha1.Argument{
+		{Name: "output", Default: "out.csv"},
+		{Name: "env"},
+		{Name: "input-file"},
+	}}}
+	toolFlags := addToolFlags(flags, tool)
+	if _, exists := toolFlags["env"]; exists {
+		t.Errorf("the argument env took over the --env flag")
+	}
+	if err := flags.Parse([]string{"--output", "x.csv", "--input-file", "data.csv", "-d"}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if got := toolFlags["output"].Value.String(); got != "x.csv" || output != "name" {
+		t.Errorf("argument output = %q and run flag = %q, want x.csv and name", got, output)
+	}
+	if got := toolFlags["input-file"].Value.String(); got != "data.csv" {
+		t.Errorf("argument input-file = %q, want data.csv", got)
+	}
+	if !detach {
+		t.Errorf("--detach was not set, although the tool has no detach argument")
+	}
+}
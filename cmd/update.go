@@ -0,0 +1,95 @@
+/*
+Copyright © 2025 Serge Matveenko <lig@countzero.co>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"codeberg.org/lig/rapt/internal/app/rapt"
+	"github.com/spf13/cobra"
+)
+
+// Update command flags
+var (
+	updateOptions  rapt.ToolOptions
+	updateUnsetEnv []string
+	updateCluster  bool
+	updateDryRun   bool
+)
+
+// updateCmd represents the update command
+var updateCmd = &cobra.Command{
+	Use:   "update <tool-name>",
+	Short: "Change settings of an existing tool",
+	Long: `Change settings of an existing tool with the same flags as 'rapt add'.
+
+Only the given settings are changed, everything else is kept. Environment variables
+replace those with the same name and --unset-env removes them; --env-from-secret and
+--env-from-configmap add imports; resource flags change only the given quantities.
+The command is replaced as a whole when --command, --command-arg or --shell is given;
+use --command "" to run the entrypoint of the image again.
+
+The change is recorded as a new revision of the tool, see 'rapt history'. If the tool
+is changed by someone else at the same time, the update is applied on top of that change.
+
+Examples:
+  rapt update echo-tool --image busybox:1.36
+  rapt update report -e LOG_LEVEL=debug --unset-env DEBUG
+  rapt update report --memory-limit 1Gi
+  rapt update curl --cluster --command "curl -sS"
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkEnvFlags(updateOptions.Env); err != nil {
+			return err
+		}
+		update := rapt.ToolUpdate{
+			Options:           updateOptions,
+			UnsetEnv:          updateUnsetEnv,
+			SetCommand:        cmd.Flags().Changed("command") || cmd.Flags().Changed("command-arg") || cmd.Flags().Changed("shell"),
+			SetServiceAccount: cmd.Flags().Changed("service-account"),
+			SetRestricted:     cmd.Flags().Changed("restricted"),
+		}
+		return rapt.UpdateTool(namespace, args[0], update, updateCluster, updateDryRun)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(updateCmd)
+
+	updateCmd.Flags().StringVarP(&updateOptions.Image, "image", "i", "", "Container image to run.")
+	updateCmd.Flags().StringVarP(&updateOptions.Command, "command", "c", "", "Command to run (overrides ENTRYPOINT). Specify as a single string, split with shell quoting rules; an empty string removes it.")
+	updateCmd.Flags().StringArrayVar(&updateOptions.CommandArgs, "command-arg", nil, "Argument appended to the command exactly as given. Can be specified multiple times.")
+	updateCmd.Flags().BoolVar(&updateOptions.Shell, "shell", false, "Run --command as a shell script with sh -c; --command-arg values and tool arguments become $1, $2, ...")
+	updateCmd.Flags().StringArrayVarP(&updateOptions.Env, "env", "e", nil, "Set an environment variable in the form NAME=VALUE. Can be specified multiple times.")
+	updateCmd.Flags().StringArrayVar(&updateUnsetEnv, "unset-env", nil, "Remove the environment variable with this name. Can be specified multiple times.")
+	updateCmd.Flags().StringArrayVar(&updateOptions.SecretEnv, "env-secret", nil, "Set an environment variable read from a Secret key in the form NAME=SECRET:KEY. Can be specified multiple times.")
+	updateCmd.Flags().StringArrayVar(&updateOptions.ConfigMapEnv, "env-configmap", nil, "Set an environment variable read from a ConfigMap key in the form NAME=CONFIGMAP:KEY. Can be specified multiple times.")
+	updateCmd.Flags().StringArrayVar(&updateOptions.EnvFromSecrets, "env-from-secret", nil, "Import all keys of a Secret as environment variables. Can be specified multiple times.")
+	updateCmd.Flags().StringArrayVar(&updateOptions.EnvFromConfigMaps, "env-from-configmap", nil, "Import all keys of a ConfigMap as environment variables. Can be specified multiple times.")
+	updateCmd.Flags().StringVar(&updateOptions.Resources.CPU, "cpu", "", "CPU request of the tool container, e.g. 500m")
+	updateCmd.Flags().StringVar(&updateOptions.Resources.Memory, "memory", "", "Memory request of the tool container, e.g. 256Mi")
+	updateCmd.Flags().StringVar(&updateOptions.Resources.CPULimit, "cpu-limit", "", "CPU limit of the tool container")
+	updateCmd.Flags().StringVar(&updateOptions.Resources.MemoryLimit, "memory-limit", "", "Memory limit of the tool container")
+	updateCmd.Flags().StringVar(&updateOptions.ServiceAccount, "service-account", "", "Service account the tool pod runs as, an empty string removes it")
+	updateCmd.Flags().BoolVar(&updateOptions.Restricted, "restricted", false, "Run the tool with the restricted security defaults; --restricted=false turns them off")
+	updateCmd.Flags().BoolVar(&updateCluster, "cluster", false, "Update a cluster-scoped ClusterTool")
+	updateCmd.Flags().BoolVar(&updateDryRun, "dry-run", false, "Print the updated Tool CR YAML without applying it to the cluster")
+}
@@ -0,0 +1,63 @@
+/*
+Copyright © 2025 Serge Matveenko <lig@countzero.co>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"codeberg.org/lig/rapt/internal/app/rapt"
+	"github.com/spf13/cobra"
+)
+
+var (
+	validateFiles  []string
+	validateOutput string
+)
+
+// validateCmd represents the validate command
+var validateCmd = &cobra.Command{
+	Use:   "validate -f <file|directory|->",
+	Short: "Check tool manifests against the Tool schema without a cluster",
+	Long: `Check Tool and ClusterTool manifests against the OpenAPI schema of the Tool CRD
+embedded in rapt and against the rules the API server enforces, without contacting
+a cluster. Files and directories are read like with 'rapt apply'.
+
+Every problem is reported, in the format chosen with --output: text, json or sarif.
+The command fails if any problem is found, so it can guard tool manifests in CI.
+Use 'rapt lint' to check best practices as well.
+
+Examples:
+  rapt validate -f examples/
+  rapt validate -f tools/ --output json
+  rapt validate -f tools/ --output sarif > rapt.sarif`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return rapt.Validate(validateFiles, validateOutput)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+
+	validateCmd.Flags().StringArrayVarP(&validateFiles, "filename", "f", nil, "File, directory or - for stdin with the manifests to check. Can be specified multiple times.")
+	validateCmd.MarkFlagRequired("filename")
+	validateCmd.Flags().StringVarP(&validateOutput, "output", "o", rapt.FormatText, "Report format: text, json or sarif")
+}
@@ -2,62 +2,108 @@ package rapt
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
 
+	"codeberg.org/lig/rapt/api/v1alpha1"
 	"codeberg.org/lig/rapt/internal/k8s"
-
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime/schema"
+	"github.com/kballard/go-shellquote"
+	corev1 "k8s.io/api/core/v1"
 )
 
+// ToolOptions holds the tool settings given on the command line
+type ToolOptions struct {
+	Image string
+	// Command is split into the command argv with POSIX shell quoting rules
+	Command string
+	// CommandArgs are appended to the command argv unchanged
+	CommandArgs []string
+	// Shell runs Command as a script with sh -c instead of splitting it
+	Shell bool
+	// Env holds plain environment variables in the form NAME=VALUE
+	Env []string
+	// SecretEnv holds environment variables in the form NAME=SECRET:KEY
+	SecretEnv []string
+	// ConfigMapEnv holds environment variables in the form NAME=CONFIGMAP:KEY
+	ConfigMapEnv []string
+	// EnvFromSecrets holds Secrets whose keys all become environment variables
+	EnvFromSecrets []string
+	// EnvFromConfigMaps holds ConfigMaps whose keys all become environment variables
+	EnvFromConfigMaps []string
+	Resources         ResourceSpec
+	// ServiceAccount is the service account the tool pod runs as
+	ServiceAccount string
+	// Restricted enables the restricted security defaults for the tool
+	Restricted bool
+}
+
 // Add registers a new tool definition in the Kubernetes cluster.
-func Add(namespace, name, image, command string, env []string, dryRun bool) error {
+// With cluster set, it is created as a ClusterTool that is available in every namespace.
+func Add(namespace, name string, opts ToolOptions, cluster, dryRun bool) error {
 	// Validate required fields
 	if name == "" {
 		return fmt.Errorf("tool name is required")
 	}
-	if image == "" {
+	if opts.Image == "" {
 		return fmt.Errorf("container image is required")
 	}
 
+	tool, err := buildTool(namespace, name, opts)
+	if err != nil {
+		return err
+	}
+	return createTool(namespace, tool, cluster, dryRun)
+}
+
+// buildTool prepares a Tool from the settings given on the command line
+func buildTool(namespace, name string, opts ToolOptions) (*v1alpha1.Tool, error) {
 	// Prepare env variables for the Tool spec
-	envVars := make([]map[string]any, len(env))
-	for i, e := range env {
-		parts := strings.SplitN(e, "=", 2)
-		if len(parts) != 2 {
-			return fmt.Errorf("invalid environment variable format: %s (expected NAME=VALUE)", e)
-		}
-		envVars[i] = map[string]any{
-			"name":  strings.TrimSpace(parts[0]),
-			"value": strings.TrimSpace(parts[1]),
-		}
+	envVars, err := buildEnv(opts)
+	if err != nil {
+		return nil, err
 	}
 
 	// Prepare the Tool object
-	jobTemplate := map[string]any{
-		"image": image,
-		"env":   envVars,
-	}
-	
-	// Only add command if it's not empty
-	if command != "" {
-		jobTemplate["command"] = strings.Fields(command)
-	}
-
-	tool := &unstructured.Unstructured{
-		Object: map[string]any{
-			"apiVersion": "rapt.dev/v1alpha1",
-			"kind":       "Tool",
-			"metadata": map[string]any{
-				"name":      name,
-				"namespace": namespace,
-			},
-			"spec": map[string]any{
-				"jobTemplate": jobTemplate,
-			},
-		},
+	tool := v1alpha1.NewTool(namespace, name)
+	tool.Spec.JobTemplate = v1alpha1.JobTemplate{
+		Image:              opts.Image,
+		Env:                envVars,
+		EnvFrom:            buildEnvFrom(opts),
+		ServiceAccountName: opts.ServiceAccount,
+	}
+	if opts.Restricted {
+		tool.Spec.JobTemplate.SecurityProfile = v1alpha1.SecurityProfileRestricted
+	}
+
+	command, err := buildCommand(opts)
+	if err != nil {
+		return nil, err
+	}
+	tool.Spec.JobTemplate.Command = command
+
+	// Only add resources if any were given
+	if !opts.Resources.IsEmpty() {
+		requirements, err := mergeResources(nil, opts.Resources)
+		if err != nil {
+			return nil, err
+		}
+		tool.Spec.JobTemplate.Resources = requirements
+	}
+	return tool, nil
+}
+
+// createTool creates a prepared tool, as a ClusterTool with cluster set.
+// In dry-run mode it only prints the YAML of the tool.
+func createTool(namespace string, tool *v1alpha1.Tool, cluster, dryRun bool) error {
+	name := tool.Name
+	if dryRun {
+		printArgv(tool.Spec.JobTemplate.Command)
+	}
+	if cluster {
+		return addClusterTool(namespace, name, tool.Spec, dryRun)
 	}
 
 	// If dry-run mode, print YAML and exit
@@ -65,23 +111,168 @@ func Add(namespace, name, image, command string, env []string, dryRun bool) erro
 		return k8s.PrintToolYAML(tool)
 	}
 
-	// Initialize dynamic client
-	dynClient, err := k8s.InitDynamicClient(namespace)
+	// Initialize tool client
+	toolClient, err := k8s.InitToolClient(namespace)
 	if err != nil {
 		return err
 	}
 
-	gvr := schema.GroupVersionResource{
-		Group:    "rapt.dev",
-		Version:  "v1alpha1",
-		Resource: "tools",
-	}
-
-	_, err = dynClient.Resource(gvr).Namespace(namespace).Create(context.Background(), tool, metav1.CreateOptions{})
+	created, err := toolClient.Create(context.Background(), tool)
 	if err != nil {
 		return fmt.Errorf("failed to create tool %s: %w", name, err)
 	}
+	if _, err := saveToolRevision(toolClient, created, "rapt add"); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v (run 'rapt init' to upgrade the CRDs)\n", err)
+	}
+	recordArgumentCount(toolClient, v1alpha1.ToolKind, namespace, name)
 
 	fmt.Printf("Successfully created tool '%s' in namespace '%s'\n", name, namespace)
 	return nil
 }
+
+// addClusterTool registers a new tool definition that is available in every namespace
+func addClusterTool(namespace, name string, spec v1alpha1.ToolSpec, dryRun bool) error {
+	clusterTool := v1alpha1.NewClusterTool(name)
+	clusterTool.Spec = spec
+
+	// If dry-run mode, print YAML and exit
+	if dryRun {
+		return k8s.PrintClusterToolYAML(clusterTool)
+	}
+
+	// Initialize tool client
+	toolClient, err := k8s.InitToolClient(namespace)
+	if err != nil {
+		return err
+	}
+
+	_, err = toolClient.CreateClusterTool(context.Background(), clusterTool)
+	if err != nil {
+		return fmt.Errorf("failed to create cluster tool %s: %w", name, err)
+	}
+	recordArgumentCount(toolClient, v1alpha1.ClusterToolKind, "", name)
+
+	fmt.Printf("Successfully created cluster tool '%s'\n", name)
+	return nil
+}
+
+// buildCommand returns the command argv of a tool. The command is split with POSIX shell
+// quoting rules and the command args are appended unchanged. In shell mode the command is
+// run as a script by sh -c, and the command args and the tool arguments become its
+// positional parameters $1, $2 and so on.
+func buildCommand(opts ToolOptions) ([]string, error) {
+	if opts.Shell {
+		if opts.Command == "" {
+			return nil, errors.New("--shell requires a --command to run")
+		}
+		return append([]string{"sh", "-c", opts.Command, "sh"}, opts.CommandArgs...), nil
+	}
+
+	var command []string
+	if opts.Command != "" {
+		var err error
+		command, err = shellquote.Split(opts.Command)
+		if err != nil {
+			return nil, fmt.Errorf("invalid command %q: %w", opts.Command, err)
+		}
+	}
+	return append(command, opts.CommandArgs...), nil
+}
+
+// printArgv prints the exact command argv of a tool as a YAML comment, for dry runs
+func printArgv(command []string) {
+	if len(command) == 0 {
+		return
+	}
+	quoted := make([]string, len(command))
+	for i, arg := range command {
+		quoted[i] = strconv.Quote(arg)
+	}
+	fmt.Printf("# command argv: [%s]\n", strings.Join(quoted, ", "))
+}
+
+// buildEnv converts plain and referenced environment variables into EnvVars
+func buildEnv(opts ToolOptions) ([]corev1.EnvVar, error) {
+	var envVars []corev1.EnvVar
+	for _, e := range opts.Env {
+		parts := strings.SplitN(e, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid environment variable format: %s (expected NAME=VALUE)", e)
+		}
+		envVars = append(envVars, corev1.EnvVar{
+			Name:  strings.TrimSpace(parts[0]),
+			Value: strings.TrimSpace(parts[1]),
+		})
+	}
+
+	for _, e := range opts.SecretEnv {
+		name, objectName, key, err := parseEnvReference(e, "SECRET")
+		if err != nil {
+			return nil, err
+		}
+		envVars = append(envVars, corev1.EnvVar{
+			Name: name,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: objectName},
+					Key:                  key,
+				},
+			},
+		})
+	}
+
+	for _, e := range opts.ConfigMapEnv {
+		name, objectName, key, err := parseEnvReference(e, "CONFIGMAP")
+		if err != nil {
+			return nil, err
+		}
+		envVars = append(envVars, corev1.EnvVar{
+			Name: name,
+			ValueFrom: &corev1.EnvVarSource{
+				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: objectName},
+					Key:                  key,
+				},
+			},
+		})
+	}
+
+	return envVars, nil
+}
+
+// parseEnvReference parses a NAME=OBJECT:KEY environment variable reference
+func parseEnvReference(e, objectKind string) (name, objectName, key string, err error) {
+	parts := strings.SplitN(e, "=", 2)
+	if len(parts) == 2 {
+		ref := strings.SplitN(parts[1], ":", 2)
+		if len(ref) == 2 {
+			name = strings.TrimSpace(parts[0])
+			objectName = strings.TrimSpace(ref[0])
+			key = strings.TrimSpace(ref[1])
+		}
+	}
+	if name == "" || objectName == "" || key == "" {
+		return "", "", "", fmt.Errorf("invalid environment variable reference: %s (expected NAME=%s:KEY)", e, objectKind)
+	}
+	return name, objectName, key, nil
+}
+
+// buildEnvFrom converts whole Secret and ConfigMap references into EnvFromSources
+func buildEnvFrom(opts ToolOptions) []corev1.EnvFromSource {
+	var envFrom []corev1.EnvFromSource
+	for _, name := range opts.EnvFromSecrets {
+		envFrom = append(envFrom, corev1.EnvFromSource{
+			SecretRef: &corev1.SecretEnvSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: name},
+			},
+		})
+	}
+	for _, name := range opts.EnvFromConfigMaps {
+		envFrom = append(envFrom, corev1.EnvFromSource{
+			ConfigMapRef: &corev1.ConfigMapEnvSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: name},
+			},
+		})
+	}
+	return envFrom
+}
@@ -0,0 +1,51 @@
+package rapt
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBuildCommand(t *testing.T) {
+	tests := []struct {
+		name        string
+		command     string
+		commandArgs []string
+		shell       bool
+		want        []string
+		wantErr     string
+	}{
+		{name: "no command", want: nil},
+		{name: "words", command: "ls -la /data", want: []string{"ls", "-la", "/data"}},
+		{name: "extra whitespace", command: "  ls   -la  ", want: []string{"ls", "-la"}},
+		{name: "single quotes", command: `sh -c 'echo $HOME'`, want: []string{"sh", "-c", "echo $HOME"}},
+		{name: "double quotes", command: `echo "Hello from Rapt!"`, want: []string{"echo", "Hello from Rapt!"}},
+		{name: "escaped space", command: `cat my\ file.txt`, want: []string{"cat", "my file.txt"}},
+		{name: "nested quotes", command: `sh -c "echo 'a b'"`, want: []string{"sh", "-c", "echo 'a b'"}},
+		{name: "empty quoted argument", command: `printf '%s' ''`, want: []string{"printf", "%s", ""}},
+		{name: "unterminated quote", command: `echo "oops`, wantErr: `invalid command "echo \"oops"`},
+		{name: "command args are appended verbatim", command: "psql -c", commandArgs: []string{"SELECT 'a b';", "$X"}, want: []string{"psql", "-c", "SELECT 'a b';", "$X"}},
+		{name: "command args without a command", commandArgs: []string{"--help"}, want: []string{"--help"}},
+		{name: "shell", command: "echo $HOME | tr a-z A-Z", shell: true, want: []string{"sh", "-c", "echo $HOME | tr a-z A-Z", "sh"}},
+		{name: "shell is not split", command: `echo "unterminated`, shell: true, want: []string{"sh", "-c", `echo "unterminated`, "sh"}},
+		{name: "shell with positional parameters", command: `echo "$1-$2"`, commandArgs: []string{"a b", "c"}, shell: true, want: []string{"sh", "-c", `echo "$1-$2"`, "sh", "a b", "c"}},
+		{name: "shell without a command", shell: true, wantErr: "--shell requires a --command"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildCommand(ToolOptions{Command: tt.command, CommandArgs: tt.commandArgs, Shell: tt.shell})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("buildCommand() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("buildCommand() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("buildCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
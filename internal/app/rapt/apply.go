@@ -0,0 +1,356 @@
+package rapt
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"codeberg.org/lig/rapt/api/v1alpha1"
+	"codeberg.org/lig/rapt/internal/k8s"
+	apiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	yamlutil "k8s.io/apimachinery/pkg/util/yaml"
+	yamlv2 "sigs.k8s.io/yaml"
+)
+
+// Dry-run modes of Apply
+const (
+	DryRunNone   = "none"
+	DryRunClient = "client"
+	DryRunServer = "server"
+)
+
+// Results of applying a manifest
+const (
+	applyCreated    = "created"
+	applyConfigured = "configured"
+	applyUnchanged  = "unchanged"
+)
+
+// manifestExtensions are the file extensions read from a directory
+var manifestExtensions = []string{".yaml", ".yml", ".json"}
+
+// manifest is a Tool or ClusterTool document read from a file
+type manifest struct {
+	// source names the file and the document within it, for messages
+	source string
+	// file and document locate the manifest for reports that refer to them separately
+	file     string
+	document int
+	object   *unstructured.Unstructured
+	// defaulted is the object with the schema defaults applied, as the API server would store it
+	defaulted *unstructured.Unstructured
+}
+
+// Apply creates or updates the Tools and ClusterTools defined in the given files with server-side apply.
+// A path is a file, a directory whose .yaml, .yml and .json files are read, or - for stdin;
+// files may contain several YAML documents. Every document is checked against the CRD schema
+// and the tool validation first, and nothing is applied if any of them is invalid.
+func Apply(namespace string, paths []string, dryRun string) error {
+	if !slices.Contains([]string{DryRunNone, DryRunClient, DryRunServer}, dryRun) {
+		return fmt.Errorf("invalid --dry-run value %q, must be one of %s, %s or %s", dryRun, DryRunNone, DryRunClient, DryRunServer)
+	}
+
+	manifests, err := readManifests(paths)
+	if err != nil {
+		return err
+	}
+	if len(manifests) == 0 {
+		return fmt.Errorf("no tools found in %s", strings.Join(paths, ", "))
+	}
+
+	defaultNamespace, err := k8s.CurrentNamespace(namespace)
+	if err != nil {
+		return fmt.Errorf("failed to determine the namespace: %w", err)
+	}
+	if err := checkManifests(manifests, namespace, defaultNamespace); err != nil {
+		return err
+	}
+
+	toolClient, err := k8s.InitToolClient(namespace)
+	if err != nil {
+		return fmt.Errorf("failed to initialize tool client: %w", err)
+	}
+
+	suffix := ""
+	switch dryRun {
+	case DryRunClient:
+		suffix = " (dry run)"
+	case DryRunServer:
+		suffix = " (server dry run)"
+	}
+
+	counts := make(map[string]int)
+	failed := 0
+	for _, m := range manifests {
+		result, err := applyManifest(toolClient, m, dryRun)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", m.source, err)
+			failed++
+			continue
+		}
+		counts[result]++
+		fmt.Printf("%s %s%s\n", describeManifest(m), result, suffix)
+	}
+
+	fmt.Printf("\n%d created, %d configured, %d unchanged%s\n", counts[applyCreated], counts[applyConfigured], counts[applyUnchanged], suffix)
+	if failed > 0 {
+		return fmt.Errorf("failed to apply %d of %d tools", failed, len(manifests))
+	}
+	return nil
+}
+
+// readManifests reads every document of the given files, directories and stdin
+func readManifests(paths []string) ([]manifest, error) {
+	var manifests []manifest
+	for _, path := range paths {
+		if path == "-" {
+			read, err := readDocuments("stdin", os.Stdin)
+			if err != nil {
+				return nil, err
+			}
+			manifests = append(manifests, read...)
+			continue
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		files := []string{path}
+		if info.IsDir() {
+			entries, err := os.ReadDir(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read directory %s: %w", path, err)
+			}
+			files = nil
+			for _, entry := range entries {
+				if !entry.IsDir() && slices.Contains(manifestExtensions, strings.ToLower(filepath.Ext(entry.Name()))) {
+					files = append(files, filepath.Join(path, entry.Name()))
+				}
+			}
+		}
+
+		for _, file := range files {
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", file, err)
+			}
+			read, err := readDocuments(file, bytes.NewReader(content))
+			if err != nil {
+				return nil, err
+			}
+			manifests = append(manifests, read...)
+		}
+	}
+	return manifests, nil
+}
+
+// readDocuments splits a YAML or JSON stream into documents, skipping empty ones
+func readDocuments(name string, r io.Reader) ([]manifest, error) {
+	reader := yamlutil.NewYAMLReader(bufio.NewReader(r))
+	var manifests []manifest
+	for i := 1; ; i++ {
+		document, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return manifests, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+
+		source := fmt.Sprintf("%s (document %d)", name, i)
+		jsonBytes, err := yamlv2.YAMLToJSON(document)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid YAML: %w", source, err)
+		}
+		if trimmed := bytes.TrimSpace(jsonBytes); len(trimmed) == 0 || string(trimmed) == "null" {
+			continue
+		}
+		object := &unstructured.Unstructured{}
+		if err := object.UnmarshalJSON(jsonBytes); err != nil {
+			return nil, fmt.Errorf("%s: %w", source, err)
+		}
+		manifests = append(manifests, manifest{source: source, file: name, document: i, object: object})
+	}
+}
+
+// checkManifests checks every manifest and sets the namespace of Tools that have none.
+// All problems are reported together.
+func checkManifests(manifests []manifest, namespace, defaultNamespace string) error {
+	validators, err := loadSchemaValidators()
+	if err != nil {
+		return err
+	}
+
+	var problems []string
+	seen := make(map[string]string)
+	for i := range manifests {
+		m := &manifests[i]
+		for _, err := range checkManifest(m, validators, namespace, defaultNamespace) {
+			problems = append(problems, fmt.Sprintf("  %s: %v", m.source, err))
+		}
+		key := describeManifest(*m) + " " + m.object.GetNamespace()
+		if first, exists := seen[key]; exists {
+			problems = append(problems, fmt.Sprintf("  %s: %s is already defined in %s", m.source, describeManifest(*m), first))
+		}
+		seen[key] = m.source
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid manifests, nothing was applied:\n%s", strings.Join(problems, "\n"))
+	}
+	return nil
+}
+
+// loadSchemaValidators returns the schema validators of Tools and ClusterTools by kind
+func loadSchemaValidators() (map[string]*k8s.SchemaValidator, error) {
+	validators := make(map[string]*k8s.SchemaValidator)
+	for kind, load := range map[string]func() (*apiv1.CustomResourceDefinition, error){
+		v1alpha1.ToolKind:        k8s.LoadToolCRD,
+		v1alpha1.ClusterToolKind: k8s.LoadClusterToolCRD,
+	} {
+		crd, err := load()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load CRD: %w", err)
+		}
+		validators[kind], err = k8s.NewSchemaValidator(crd)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return validators, nil
+}
+
+// checkManifest checks a single manifest against the CRD schema and the tool validation
+func checkManifest(m *manifest, validators map[string]*k8s.SchemaValidator, namespace, defaultNamespace string) []error {
+	object := m.object
+	if object.GetAPIVersion() != v1alpha1.GroupVersion.String() {
+		return []error{fmt.Errorf("unsupported apiVersion %q, expected %s", object.GetAPIVersion(), v1alpha1.GroupVersion)}
+	}
+	validator, supported := validators[object.GetKind()]
+	if !supported {
+		if object.GetKind() == v1alpha1.ToolRevisionKind {
+			return []error{errors.New("ToolRevisions are recorded by rapt and cannot be applied")}
+		}
+		return []error{fmt.Errorf("unsupported kind %q, expected %s or %s", object.GetKind(), v1alpha1.ToolKind, v1alpha1.ClusterToolKind)}
+	}
+	if object.GetName() == "" {
+		return []error{errors.New("metadata.name is required")}
+	}
+
+	switch object.GetKind() {
+	case v1alpha1.ToolKind:
+		if object.GetNamespace() == "" {
+			object.SetNamespace(defaultNamespace)
+		} else if namespace != "" && object.GetNamespace() != namespace {
+			return []error{fmt.Errorf("the namespace of the tool (%s) does not match --namespace %s", object.GetNamespace(), namespace)}
+		}
+	case v1alpha1.ClusterToolKind:
+		if object.GetNamespace() != "" {
+			return []error{errors.New("a ClusterTool is cluster-scoped and cannot have a namespace")}
+		}
+	}
+
+	if errs := validator.Validate(object.Object); len(errs) > 0 {
+		return errs
+	}
+
+	m.defaulted = object.DeepCopy()
+	validator.Default(m.defaulted.Object)
+	var err error
+	if object.GetKind() == v1alpha1.ClusterToolKind {
+		_, err = v1alpha1.ClusterToolFromUnstructured(m.defaulted)
+	} else {
+		_, err = v1alpha1.ToolFromUnstructured(m.defaulted)
+	}
+	if err != nil {
+		return []error{err}
+	}
+	return nil
+}
+
+// applyManifest applies a checked manifest and reports whether the object was created, configured or unchanged
+func applyManifest(toolClient *k8s.ToolClient, m manifest, dryRun string) (string, error) {
+	object := m.object
+	existing, err := toolClient.GetObject(context.TODO(), object.GetKind(), object.GetNamespace(), object.GetName())
+	if err != nil && !apierrors.IsNotFound(err) {
+		return "", fmt.Errorf("failed to get %s: %w", describeManifest(m), err)
+	}
+	if err != nil {
+		existing = nil
+	}
+
+	if dryRun == DryRunClient {
+		switch {
+		case existing == nil:
+			return applyCreated, nil
+		case manifestChanges(existing, m.defaulted):
+			return applyConfigured, nil
+		default:
+			return applyUnchanged, nil
+		}
+	}
+
+	applied, err := toolClient.ApplyObject(context.TODO(), object, dryRun == DryRunServer)
+	if err != nil {
+		return "", fmt.Errorf("failed to apply %s: %w", describeManifest(m), err)
+	}
+
+	result := appliedResult(existing, applied)
+	if dryRun == DryRunNone && result != applyUnchanged {
+		if err := saveObjectRevision(toolClient, applied, "rapt apply"); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s was applied, but its revision was not recorded: %v\n", describeManifest(m), err)
+		}
+		recordArgumentCount(toolClient, applied.GetKind(), applied.GetNamespace(), applied.GetName())
+	}
+	return result, nil
+}
+
+// appliedResult tells whether an apply created, configured or left unchanged the existing object, which is nil if there was none
+func appliedResult(existing, applied *unstructured.Unstructured) string {
+	if existing == nil {
+		return applyCreated
+	}
+	// The API server only increments the generation when the spec changes
+	if applied.GetGeneration() != existing.GetGeneration() ||
+		!maps.Equal(applied.GetLabels(), existing.GetLabels()) ||
+		!maps.Equal(applied.GetAnnotations(), existing.GetAnnotations()) {
+		return applyConfigured
+	}
+	return applyUnchanged
+}
+
+// manifestChanges reports whether applying a manifest would change the existing object.
+// Labels and annotations that the manifest leaves out are kept by server-side apply and are not compared.
+func manifestChanges(existing, desired *unstructured.Unstructured) bool {
+	if !equality.Semantic.DeepEqual(existing.Object["spec"], desired.Object["spec"]) {
+		return true
+	}
+	for _, pair := range [][2]map[string]string{
+		{existing.GetLabels(), desired.GetLabels()},
+		{existing.GetAnnotations(), desired.GetAnnotations()},
+	} {
+		for key, value := range pair[1] {
+			if current, exists := pair[0][key]; !exists || current != value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// describeManifest names the object of a manifest like kubectl does, e.g. tool.rapt.dev/echo
+func describeManifest(m manifest) string {
+	return fmt.Sprintf("%s.%s/%s", strings.ToLower(m.object.GetKind()), v1alpha1.GroupVersion.Group, m.object.GetName())
+}
@@ -0,0 +1,401 @@
+package rapt
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	yamlv2 "sigs.k8s.io/yaml"
+)
+
+// toolHeader starts the documents of tests that only set metadata and spec
+const toolHeader = "apiVersion: rapt.dev/v1alpha1\nkind: Tool\n"
+
+// decodeManifest decodes a YAML document into an unstructured object
+func decodeManifest(t *testing.T, document string) *unstructured.Unstructured {
+	t.Helper()
+	jsonBytes, err := yamlv2.YAMLToJSON([]byte(document))
+	if err != nil {
+		t.Fatalf("YAMLToJSON() error = %v", err)
+	}
+	object := &unstructured.Unstructured{}
+	if err := object.UnmarshalJSON(jsonBytes); err != nil {
+		t.Fatalf("UnmarshalJSON() error = %v", err)
+	}
+	return object
+}
+
+func TestReadDocuments(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		sources []string
+		names   []string
+		wantErr string
+	}{
+		{
+			name:    "single document",
+			input:   "apiVersion: rapt.dev/v1alpha1\nkind: Tool\nmetadata:\n  name: echo\n",
+			sources: []string{"tools.yaml (document 1)"},
+			names:   []string{"echo"},
+		},
+		{
+			name:    "several documents",
+			input:   "kind: Tool\nmetadata:\n  name: echo\n---\nkind: ClusterTool\nmetadata:\n  name: curl\n",
+			sources: []string{"tools.yaml (document 1)", "tools.yaml (document 2)"},
+			names:   []string{"echo", "curl"},
+		},
+		{
+			name:    "empty and null documents are skipped but counted",
+			input:   "---\n# only a comment\n---\nnull\n---\nkind: Tool\nmetadata:\n  name: echo\n---\n",
+			sources: []string{"tools.yaml (document 3)"},
+			names:   []string{"echo"},
+		},
+		{
+			name:    "JSON",
+			input:   `{"apiVersion": "rapt.dev/v1alpha1", "kind": "Tool", "metadata": {"name": "echo"}}`,
+			sources: []string{"tools.yaml (document 1)"},
+			names:   []string{"echo"},
+		},
+		{
+			name:  "empty input",
+			input: "",
+		},
+		{
+			name:    "invalid YAML",
+			input:   "kind: Tool\n---\nkind: [Tool\n",
+			wantErr: "tools.yaml (document 2): invalid YAML",
+		},
+		{
+			name:    "not an object",
+			input:   "- kind: Tool\n",
+			wantErr: "tools.yaml (document 1): ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifests, err := readDocuments("tools.yaml", strings.NewReader(tt.input))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("readDocuments() error = %v, want an error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readDocuments() error = %v", err)
+			}
+			if len(manifests) != len(tt.sources) {
+				t.Fatalf("readDocuments() returned %d manifests, want %d", len(manifests), len(tt.sources))
+			}
+			for i, m := range manifests {
+				if m.source != tt.sources[i] || m.file != "tools.yaml" {
+					t.Errorf("manifest %d: source %q, file %q, want %q, tools.yaml", i, m.source, m.file, tt.sources[i])
+				}
+				if m.object.GetName() != tt.names[i] {
+					t.Errorf("manifest %d: name %q, want %q", i, m.object.GetName(), tt.names[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCheckManifest(t *testing.T) {
+	validators, err := loadSchemaValidators()
+	if err != nil {
+		t.Fatalf("loadSchemaValidators() error = %v", err)
+	}
+
+	const spec = "spec:\n  jobTemplate:\n    image: alpine:3.20\n"
+	tests := []struct {
+		name      string
+		document  string
+		namespace string
+		// wantNamespace is the namespace of the object after the check
+		wantNamespace string
+		want          []string
+	}{
+		{
+			name:          "tool gets the default namespace",
+			document:      "apiVersion: rapt.dev/v1alpha1\nkind: Tool\nmetadata:\n  name: echo\n" + spec,
+			wantNamespace: "team",
+		},
+		{
+			name:          "tool namespace matches --namespace",
+			document:      "apiVersion: rapt.dev/v1alpha1\nkind: Tool\nmetadata:\n  name: echo\n  namespace: ops\n" + spec,
+			namespace:     "ops",
+			wantNamespace: "ops",
+		},
+		{
+			name:          "tool namespace without --namespace",
+			document:      "apiVersion: rapt.dev/v1alpha1\nkind: Tool\nmetadata:\n  name: echo\n  namespace: ops\n" + spec,
+			wantNamespace: "ops",
+		},
+		{
+			name:          "tool namespace does not match --namespace",
+			document:      "apiVersion: rapt.dev/v1alpha1\nkind: Tool\nmetadata:\n  name: echo\n  namespace: ops\n" + spec,
+			namespace:     "team",
+			wantNamespace: "ops",
+			want:          []string{"the namespace of the tool (ops) does not match --namespace team"},
+		},
+		{
+			name:     "cluster tool",
+			document: "apiVersion: rapt.dev/v1alpha1\nkind: ClusterTool\nmetadata:\n  name: echo\n" + spec,
+		},
+		{
+			name:          "cluster tool with a namespace",
+			document:      "apiVersion: rapt.dev/v1alpha1\nkind: ClusterTool\nmetadata:\n  name: echo\n  namespace: ops\n" + spec,
+			wantNamespace: "ops",
+			want:          []string{"a ClusterTool is cluster-scoped and cannot have a namespace"},
+		},
+		{
+			name:     "unsupported apiVersion",
+			document: "apiVersion: rapt.dev/v1\nkind: Tool\nmetadata:\n  name: echo\n" + spec,
+			want:     []string{`unsupported apiVersion "rapt.dev/v1", expected rapt.dev/v1alpha1`},
+		},
+		{
+			name:     "unsupported kind",
+			document: "apiVersion: rapt.dev/v1alpha1\nkind: Job\nmetadata:\n  name: echo\n" + spec,
+			want:     []string{`unsupported kind "Job", expected Tool or ClusterTool`},
+		},
+		{
+			name:     "tool revision",
+			document: "apiVersion: rapt.dev/v1alpha1\nkind: ToolRevision\nmetadata:\n  name: echo-1\n" + spec,
+			want:     []string{"ToolRevisions are recorded by rapt and cannot be applied"},
+		},
+		{
+			name:     "missing name",
+			document: "apiVersion: rapt.dev/v1alpha1\nkind: ClusterTool\nmetadata:\n  labels:\n    team: ops\n" + spec,
+			want:     []string{"metadata.name is required"},
+		},
+		{
+			name:     "schema errors",
+			document: "apiVersion: rapt.dev/v1alpha1\nkind: ClusterTool\nmetadata:\n  name: echo\nspec:\n  jobTemplate:\n    image: alpine:3.20\n    imagePullPolicy: Always\n",
+			want:     []string{"spec.jobTemplate.imagePullPolicy: unknown field"},
+		},
+		{
+			name:     "invalid tool",
+			document: "apiVersion: rapt.dev/v1alpha1\nkind: ClusterTool\nmetadata:\n  name: echo\nspec:\n  arguments:\n    - name: level\n      type: enum\n  jobTemplate:\n    image: alpine:3.20\n",
+			want:     []string{"level"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &manifest{source: "tools.yaml (document 1)", object: decodeManifest(t, tt.document)}
+			var got []string
+			for _, err := range checkManifest(m, validators, tt.namespace, "team") {
+				got = append(got, err.Error())
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("checkManifest() = %q, want %q", got, tt.want)
+			}
+			for i := range tt.want {
+				if !strings.Contains(got[i], tt.want[i]) {
+					t.Errorf("checkManifest() error %d = %q, want it to contain %q", i, got[i], tt.want[i])
+				}
+			}
+			if namespace := m.object.GetNamespace(); namespace != tt.wantNamespace {
+				t.Errorf("namespace = %q, want %q", namespace, tt.wantNamespace)
+			}
+			if len(tt.want) == 0 && m.defaulted == nil {
+				t.Errorf("defaulted is not set")
+			}
+		})
+	}
+}
+
+func TestCheckManifestDefaults(t *testing.T) {
+	validators, err := loadSchemaValidators()
+	if err != nil {
+		t.Fatalf("loadSchemaValidators() error = %v", err)
+	}
+
+	object := decodeManifest(t, "apiVersion: rapt.dev/v1alpha1\nkind: Tool\nmetadata:\n  name: echo\nspec:\n  arguments:\n    - name: input\n  jobTemplate:\n    image: alpine:3.20\n")
+	m := &manifest{object: object}
+	if errs := checkManifest(m, validators, "", "team"); len(errs) > 0 {
+		t.Fatalf("checkManifest() = %v", errs)
+	}
+
+	argument, _, _ := unstructured.NestedSlice(m.defaulted.Object, "spec", "arguments")
+	if want := map[string]interface{}{"name": "input", "required": false, "type": "string"}; !equalJSON(argument[0], want) {
+		t.Errorf("defaulted argument = %v, want %v", argument[0], want)
+	}
+	// The object sent to the API server is left as written
+	if argument, _, _ := unstructured.NestedSlice(object.Object, "spec", "arguments"); !equalJSON(argument[0], map[string]interface{}{"name": "input"}) {
+		t.Errorf("object argument = %v, want it without defaults", argument[0])
+	}
+}
+
+// equalJSON reports whether two decoded JSON values are equal
+func equalJSON(a, b interface{}) bool {
+	x, errA := yamlv2.Marshal(a)
+	y, errB := yamlv2.Marshal(b)
+	return errA == nil && errB == nil && string(x) == string(y)
+}
+
+func TestManifestChanges(t *testing.T) {
+	const existing = `apiVersion: rapt.dev/v1alpha1
+kind: Tool
+metadata:
+  name: echo
+  namespace: team
+  generation: 3
+  labels:
+    team: ops
+    managed-by: rapt
+  annotations:
+    rapt.dev/argument-count: "1"
+spec:
+  arguments:
+    - name: input
+      required: false
+      type: string
+  jobTemplate:
+    image: alpine:3.20
+`
+
+	tests := []struct {
+		name    string
+		desired string
+		want    bool
+	}{
+		{
+			name:    "same spec",
+			desired: "metadata:\n  name: echo\nspec:\n  arguments:\n    - name: input\n      required: false\n      type: string\n  jobTemplate:\n    image: alpine:3.20\n",
+			want:    false,
+		},
+		{
+			name:    "spec changed",
+			desired: "metadata:\n  name: echo\nspec:\n  arguments:\n    - name: input\n      required: false\n      type: string\n  jobTemplate:\n    image: alpine:3.21\n",
+			want:    true,
+		},
+		{
+			name:    "subset of the labels",
+			desired: "metadata:\n  name: echo\n  labels:\n    team: ops\nspec:\n  arguments:\n    - name: input\n      required: false\n      type: string\n  jobTemplate:\n    image: alpine:3.20\n",
+			want:    false,
+		},
+		{
+			name:    "label changed",
+			desired: "metadata:\n  name: echo\n  labels:\n    team: dev\nspec:\n  arguments:\n    - name: input\n      required: false\n      type: string\n  jobTemplate:\n    image: alpine:3.20\n",
+			want:    true,
+		},
+		{
+			name:    "annotation added",
+			desired: "metadata:\n  name: echo\n  annotations:\n    owner: ops\nspec:\n  arguments:\n    - name: input\n      required: false\n      type: string\n  jobTemplate:\n    image: alpine:3.20\n",
+			want:    true,
+		},
+		{
+			name:    "spec removed",
+			desired: "metadata:\n  name: echo\n",
+			want:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := manifestChanges(decodeManifest(t, existing), decodeManifest(t, toolHeader+tt.desired)); got != tt.want {
+				t.Errorf("manifestChanges() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppliedResult(t *testing.T) {
+	const existing = "metadata:\n  name: echo\n  generation: 3\n  labels:\n    team: ops\n  annotations:\n    owner: ops\n"
+
+	tests := []struct {
+		name     string
+		existing string
+		applied  string
+		want     string
+	}{
+		{
+			name:    "no existing object",
+			applied: "metadata:\n  name: echo\n  generation: 1\n",
+			want:    applyCreated,
+		},
+		{
+			name:     "nothing changed",
+			existing: existing,
+			applied:  existing,
+			want:     applyUnchanged,
+		},
+		{
+			name:     "spec changed",
+			existing: existing,
+			applied:  "metadata:\n  name: echo\n  generation: 4\n  labels:\n    team: ops\n  annotations:\n    owner: ops\n",
+			want:     applyConfigured,
+		},
+		{
+			name:     "label changed",
+			existing: existing,
+			applied:  "metadata:\n  name: echo\n  generation: 3\n  labels:\n    team: dev\n  annotations:\n    owner: ops\n",
+			want:     applyConfigured,
+		},
+		{
+			name:     "annotation added",
+			existing: existing,
+			applied:  "metadata:\n  name: echo\n  generation: 3\n  labels:\n    team: ops\n  annotations:\n    owner: ops\n    reviewed: \"true\"\n",
+			want:     applyConfigured,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var existing *unstructured.Unstructured
+			if tt.existing != "" {
+				existing = decodeManifest(t, toolHeader+tt.existing)
+			}
+			if got := appliedResult(existing, decodeManifest(t, toolHeader+tt.applied)); got != tt.want {
+				t.Errorf("appliedResult() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyManifestClientDryRun(t *testing.T) {
+	validators, err := loadSchemaValidators()
+	if err != nil {
+		t.Fatalf("loadSchemaValidators() error = %v", err)
+	}
+	toolClient := newFakeToolClient(t, newTestTool("report", "11111111-aaaa"))
+
+	tests := []struct {
+		name     string
+		document string
+		want     string
+	}{
+		{
+			name:     "new tool",
+			document: "apiVersion: rapt.dev/v1alpha1\nkind: Tool\nmetadata:\n  name: echo\nspec:\n  jobTemplate:\n    image: alpine:3.20\n",
+			want:     applyCreated,
+		},
+		{
+			name:     "same tool",
+			document: "apiVersion: rapt.dev/v1alpha1\nkind: Tool\nmetadata:\n  name: report\nspec:\n  jobTemplate:\n    image: alpine:3.20\n",
+			want:     applyUnchanged,
+		},
+		{
+			name:     "new label",
+			document: "apiVersion: rapt.dev/v1alpha1\nkind: Tool\nmetadata:\n  name: report\n  labels:\n    team: ops\nspec:\n  jobTemplate:\n    image: alpine:3.20\n",
+			want:     applyConfigured,
+		},
+		{
+			name:     "changed tool",
+			document: "apiVersion: rapt.dev/v1alpha1\nkind: Tool\nmetadata:\n  name: report\nspec:\n  jobTemplate:\n    image: alpine:3.21\n",
+			want:     applyConfigured,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := manifest{object: decodeManifest(t, tt.document)}
+			if errs := checkManifest(&m, validators, "", "default"); len(errs) > 0 {
+				t.Fatalf("checkManifest() = %v", errs)
+			}
+			got, err := applyManifest(toolClient, m, DryRunClient)
+			if err != nil {
+				t.Fatalf("applyManifest() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("applyManifest() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
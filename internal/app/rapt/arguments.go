@@ -0,0 +1,166 @@
+package rapt
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"codeberg.org/lig/rapt/api/v1alpha1"
+)
+
+// maxSuggestionDistance is the largest edit distance at which an unknown argument name
+// is still considered a typo of a declared one
+const maxSuggestionDistance = 2
+
+// ArgumentReport describes how the given --arg values were resolved against the arguments
+// declared by a tool
+type ArgumentReport struct {
+	// Values holds the value of every argument that is given or has a default
+	Values map[string]string
+	// Given lists the arguments set on the command line, in declaration order
+	Given []string
+	// Defaulted lists the arguments whose value comes from their default, in declaration order
+	Defaulted []string
+	// Missing lists the required arguments that are not given, in declaration order
+	Missing []string
+	// Unknown lists the given names that the tool does not declare, sorted by name
+	Unknown []UnknownArgument
+	// Invalid lists the given values that fail validation, in declaration order
+	Invalid []InvalidArgument
+
+	toolName string
+}
+
+// UnknownArgument is an --arg name that the tool does not declare
+type UnknownArgument struct {
+	Name string
+	// Suggestion is the closest declared argument name, if any is close enough
+	Suggestion string
+}
+
+// InvalidArgument is an --arg value that fails the argument's validation
+type InvalidArgument struct {
+	Name string
+	Err  error
+}
+
+// resolveArguments resolves the given --arg values against the tool's argument definitions.
+// It never stops at the first problem; use Err to find out whether the arguments are usable.
+func resolveArguments(tool *v1alpha1.Tool, args map[string]string) *ArgumentReport {
+	report := &ArgumentReport{
+		Values:   make(map[string]string),
+		toolName: tool.Name,
+	}
+
+	declared := make([]string, 0, len(tool.Spec.Arguments))
+	for _, arg := range tool.Spec.Arguments {
+		declared = append(declared, arg.Name)
+
+		if value, exists := args[arg.Name]; exists {
+			report.Given = append(report.Given, arg.Name)
+			report.Values[arg.Name] = value
+			if err := arg.ValidateValue(value); err != nil {
+				report.Invalid = append(report.Invalid, InvalidArgument{Name: arg.Name, Err: err})
+			}
+		} else if arg.Default != "" {
+			report.Defaulted = append(report.Defaulted, arg.Name)
+			report.Values[arg.Name] = arg.Default
+		} else if arg.Required {
+			report.Missing = append(report.Missing, arg.Name)
+		}
+	}
+
+	for name := range args {
+		if !slices.Contains(declared, name) {
+			report.Unknown = append(report.Unknown, UnknownArgument{
+				Name:       name,
+				Suggestion: suggestArgument(name, declared),
+			})
+		}
+	}
+	slices.SortFunc(report.Unknown, func(a, b UnknownArgument) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return report
+}
+
+// Err returns an error listing every unknown, missing and invalid argument, or nil if there are none
+func (r *ArgumentReport) Err() error {
+	var problems []string
+	for _, unknown := range r.Unknown {
+		problem := fmt.Sprintf("  --arg %s: unknown argument", unknown.Name)
+		if unknown.Suggestion != "" {
+			problem += fmt.Sprintf(", did you mean '%s'?", unknown.Suggestion)
+		}
+		problems = append(problems, problem)
+	}
+	for _, name := range r.Missing {
+		problems = append(problems, fmt.Sprintf("  --arg %s: required argument not provided", name))
+	}
+	for _, invalid := range r.Invalid {
+		problems = append(problems, fmt.Sprintf("  --arg %s: %v", invalid.Name, invalid.Err))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid arguments for tool '%s':\n%s", r.toolName, strings.Join(problems, "\n"))
+	}
+	return nil
+}
+
+// Print writes the resolved argument values, marking the ones that come from defaults
+func (r *ArgumentReport) Print(out io.Writer) {
+	if len(r.Given) == 0 && len(r.Defaulted) == 0 {
+		return
+	}
+
+	fmt.Fprintln(out, "Arguments:")
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, name := range r.Given {
+		fmt.Fprintf(w, "  %s\t%s\t\n", name, r.Values[name])
+	}
+	for _, name := range r.Defaulted {
+		fmt.Fprintf(w, "  %s\t%s\t(default)\n", name, r.Values[name])
+	}
+	w.Flush()
+}
+
+// suggestArgument returns the declared name closest to an unknown one, or "" if none is close enough
+func suggestArgument(name string, declared []string) string {
+	suggestion := ""
+	best := maxSuggestionDistance + 1
+	for _, candidate := range declared {
+		if strings.EqualFold(name, candidate) {
+			return candidate
+		}
+		if distance := levenshtein(strings.ToLower(name), strings.ToLower(candidate)); distance < best {
+			suggestion = candidate
+			best = distance
+		}
+	}
+	return suggestion
+}
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b string) int {
+	source, target := []rune(a), []rune(b)
+	previous := make([]int, len(target)+1)
+	current := make([]int, len(target)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(source); i++ {
+		current[0] = i
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(target)]
+}
@@ -0,0 +1,199 @@
+package rapt
+
+import (
+	"bytes"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"codeberg.org/lig/rapt/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fileProcessor is a tool with a required, an enum and an optional argument
+var fileProcessor = &v1alpha1.Tool{
+	ObjectMeta: metav1.ObjectMeta{Name: "file-processor"},
+	Spec: v1alpha1.ToolSpec{Arguments: []v1alpha1.Argument{
+		{Name: "input-file", Required: true},
+		{Name: "operation", Type: v1alpha1.ArgumentTypeEnum, Values: []string{"compress", "extract"}, Default: "compress"},
+		{Name: "retries", Type: v1alpha1.ArgumentTypeInt},
+	}},
+}
+
+func TestResolveArguments(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          map[string]string
+		wantValues    map[string]string
+		wantGiven     []string
+		wantDefaulted []string
+		wantMissing   []string
+		wantUnknown   []UnknownArgument
+		wantInvalid   []string
+		wantErr       []string
+	}{
+		{
+			name:          "defaulted argument",
+			args:          map[string]string{"input-file": "data.csv"},
+			wantValues:    map[string]string{"input-file": "data.csv", "operation": "compress"},
+			wantGiven:     []string{"input-file"},
+			wantDefaulted: []string{"operation"},
+		},
+		{
+			name:       "given argument overrides the default",
+			args:       map[string]string{"input-file": "data.csv", "operation": "extract", "retries": "3"},
+			wantValues: map[string]string{"input-file": "data.csv", "operation": "extract", "retries": "3"},
+			wantGiven:  []string{"input-file", "operation", "retries"},
+		},
+		{
+			name:          "missing required argument",
+			args:          map[string]string{},
+			wantValues:    map[string]string{"operation": "compress"},
+			wantDefaulted: []string{"operation"},
+			wantMissing:   []string{"input-file"},
+			wantErr:       []string{"--arg input-file: required argument not provided"},
+		},
+		{
+			name:          "unknown argument with a suggestion",
+			args:          map[string]string{"input-file": "data.csv", "operaton": "extract"},
+			wantValues:    map[string]string{"input-file": "data.csv", "operation": "compress"},
+			wantGiven:     []string{"input-file"},
+			wantDefaulted: []string{"operation"},
+			wantUnknown:   []UnknownArgument{{Name: "operaton", Suggestion: "operation"}},
+			wantErr:       []string{"--arg operaton: unknown argument, did you mean 'operation'?"},
+		},
+		{
+			name:          "unknown argument without a suggestion",
+			args:          map[string]string{"input-file": "data.csv", "verbose": "true"},
+			wantValues:    map[string]string{"input-file": "data.csv", "operation": "compress"},
+			wantGiven:     []string{"input-file"},
+			wantDefaulted: []string{"operation"},
+			wantUnknown:   []UnknownArgument{{Name: "verbose"}},
+			wantErr:       []string{"--arg verbose: unknown argument\n"},
+		},
+		{
+			name:        "invalid values",
+			args:        map[string]string{"input-file": "data.csv", "operation": "delete", "retries": "three"},
+			wantValues:  map[string]string{"input-file": "data.csv", "operation": "delete", "retries": "three"},
+			wantGiven:   []string{"input-file", "operation", "retries"},
+			wantInvalid: []string{"operation", "retries"},
+			wantErr:     []string{"--arg operation: ", "--arg retries: "},
+		},
+		{
+			name:          "all problems are reported together",
+			args:          map[string]string{"operaton": "x", "retries": "-"},
+			wantValues:    map[string]string{"operation": "compress", "retries": "-"},
+			wantGiven:     []string{"retries"},
+			wantDefaulted: []string{"operation"},
+			wantMissing:   []string{"input-file"},
+			wantUnknown:   []UnknownArgument{{Name: "operaton", Suggestion: "operation"}},
+			wantInvalid:   []string{"retries"},
+			wantErr: []string{
+				"invalid arguments for tool 'file-processor':",
+				"--arg operaton: unknown argument",
+				"--arg input-file: required argument not provided",
+				"--arg retries: ",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := resolveArguments(fileProcessor, tt.args)
+
+			if !maps.Equal(report.Values, tt.wantValues) {
+				t.Errorf("Values = %v, want %v", report.Values, tt.wantValues)
+			}
+			if !slices.Equal(report.Given, tt.wantGiven) {
+				t.Errorf("Given = %v, want %v", report.Given, tt.wantGiven)
+			}
+			if !slices.Equal(report.Defaulted, tt.wantDefaulted) {
+				t.Errorf("Defaulted = %v, want %v", report.Defaulted, tt.wantDefaulted)
+			}
+			if !slices.Equal(report.Missing, tt.wantMissing) {
+				t.Errorf("Missing = %v, want %v", report.Missing, tt.wantMissing)
+			}
+			if !slices.Equal(report.Unknown, tt.wantUnknown) {
+				t.Errorf("Unknown = %v, want %v", report.Unknown, tt.wantUnknown)
+			}
+			var invalid []string
+			for _, i := range report.Invalid {
+				invalid = append(invalid, i.Name)
+			}
+			if !slices.Equal(invalid, tt.wantInvalid) {
+				t.Errorf("Invalid = %v, want %v", invalid, tt.wantInvalid)
+			}
+
+			err := report.Err()
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("Err() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Err() = nil, want an error containing %q", tt.wantErr)
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error()+"\n", want) {
+					t.Errorf("Err() = %q, want it to contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestArgumentReportPrint(t *testing.T) {
+	report := resolveArguments(fileProcessor, map[string]string{"input-file": "data.csv"})
+	var out bytes.Buffer
+	report.Print(&out)
+
+	want := "Arguments:\n  input-file  data.csv  \n  operation   compress  (default)\n"
+	if out.String() != want {
+		t.Errorf("Print() = %q, want %q", out.String(), want)
+	}
+}
+
+func TestSuggestArgument(t *testing.T) {
+	declared := []string{"input-file", "operation", "output"}
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "operaton", want: "operation"},
+		{name: "Operation", want: "operation"},
+		{name: "INPUT-FILE", want: "input-file"},
+		{name: "outptu", want: "output"},
+		{name: "inputfile", want: "input-file"},
+		{name: "verbose", want: ""},
+		{name: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := suggestArgument(tt.name, declared); got != tt.want {
+				t.Errorf("suggestArgument(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"input", "input", 0},
+		{"operaton", "operation", 1},
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+		{"naïve", "naive", 1},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
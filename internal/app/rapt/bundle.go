@@ -0,0 +1,301 @@
+package rapt
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"codeberg.org/lig/rapt/api/v1alpha1"
+	"codeberg.org/lig/rapt/internal/k8s"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	yamlv2 "sigs.k8s.io/yaml"
+)
+
+// Conflict policies of Import, for tools that already exist
+const (
+	ConflictSkip      = "skip"
+	ConflictOverwrite = "overwrite"
+	ConflictFail      = "fail"
+)
+
+// Results of importing a tool, besides created and unchanged
+const (
+	importOverwritten = "overwritten"
+	importSkipped     = "skipped"
+)
+
+// serverMetadata lists the metadata fields that only make sense in the cluster an object was read from
+var serverMetadata = []string{"namespace", "uid", "resourceVersion", "generation", "creationTimestamp", "managedFields", "selfLink", "ownerReferences"}
+
+// clusterAnnotations are annotations that are not carried over to another namespace or cluster
+var clusterAnnotations = []string{v1alpha1.RevisionAnnotation, "kubectl.kubernetes.io/last-applied-configuration"}
+
+// Export writes the given tools, or all of them, as a multi-document YAML bundle to output,
+// or to stdout if output is empty. Fields set by the API server, the namespace and the status
+// are left out, so that the bundle can be imported into any namespace or cluster.
+// With cluster set, ClusterTools are exported instead of the Tools of the namespace.
+func Export(namespace string, toolNames []string, all, cluster bool, output string) error {
+	if all && len(toolNames) > 0 {
+		return errors.New("tool names and --all cannot be combined")
+	}
+	if !all && len(toolNames) == 0 {
+		return errors.New("specify the tools to export or --all")
+	}
+
+	toolClient, err := k8s.InitToolClient(namespace)
+	if err != nil {
+		return fmt.Errorf("failed to initialize tool client: %w", err)
+	}
+	kind := v1alpha1.ToolKind
+	if cluster {
+		kind = v1alpha1.ClusterToolKind
+	}
+	namespace, err = k8s.CurrentNamespace(namespace)
+	if err != nil {
+		return fmt.Errorf("failed to determine the namespace: %w", err)
+	}
+
+	var objects []unstructured.Unstructured
+	if all {
+		objects, err = toolClient.ListObjects(context.TODO(), kind, namespace)
+		if err != nil {
+			return fmt.Errorf("failed to list tools: %w", err)
+		}
+		if len(objects) == 0 {
+			return errors.New("no tools found to export")
+		}
+	}
+	for _, toolName := range toolNames {
+		object, err := toolClient.GetObject(context.TODO(), kind, namespace, toolName)
+		if apierrors.IsNotFound(err) {
+			if cluster {
+				return fmt.Errorf("cluster tool '%s' not found", toolName)
+			}
+			return fmt.Errorf("tool '%s' not found in namespace '%s'", toolName, namespace)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get tool %s: %w", toolName, err)
+		}
+		objects = append(objects, *object)
+	}
+
+	var bundle bytes.Buffer
+	for i := range objects {
+		object := &objects[i]
+		cleanObject(object)
+		content, err := yamlv2.Marshal(object.Object)
+		if err != nil {
+			return fmt.Errorf("failed to marshal tool %s: %w", object.GetName(), err)
+		}
+		if i > 0 {
+			bundle.WriteString("---\n")
+		}
+		bundle.Write(content)
+	}
+	warnMissingBases(os.Stderr, objects)
+
+	if output == "" {
+		_, err = os.Stdout.Write(bundle.Bytes())
+		return err
+	}
+	if err := os.WriteFile(output, bundle.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+	fmt.Printf("Exported %d tools to %s\n", len(objects), output)
+	return nil
+}
+
+// Import creates the Tools and ClusterTools of bundles in the cluster. Tools are imported
+// into the given namespace, or the current one, whatever namespace the bundle names.
+// onConflict decides what happens to tools that already exist and differ from the bundle: they
+// are skipped, overwritten with the bundle, or fail the whole import before anything is created.
+func Import(namespace string, paths []string, onConflict string, dryRun bool) error {
+	if !slices.Contains([]string{ConflictSkip, ConflictOverwrite, ConflictFail}, onConflict) {
+		return fmt.Errorf("invalid --on-conflict value %q, must be one of %s, %s or %s", onConflict, ConflictSkip, ConflictOverwrite, ConflictFail)
+	}
+
+	manifests, err := readManifests(paths)
+	if err != nil {
+		return err
+	}
+	if len(manifests) == 0 {
+		return fmt.Errorf("no tools found in %s", strings.Join(paths, ", "))
+	}
+
+	targetNamespace, err := k8s.CurrentNamespace(namespace)
+	if err != nil {
+		return fmt.Errorf("failed to determine the namespace: %w", err)
+	}
+	for _, m := range manifests {
+		cleanObject(m.object)
+	}
+	if err := checkManifests(manifests, "", targetNamespace); err != nil {
+		return err
+	}
+
+	toolClient, err := k8s.InitToolClient(namespace)
+	if err != nil {
+		return fmt.Errorf("failed to initialize tool client: %w", err)
+	}
+
+	// Look up every tool first, so that a failing conflict stops the import before anything is created
+	existing, conflicts, err := findExisting(toolClient, manifests)
+	if err != nil {
+		return err
+	}
+	if onConflict == ConflictFail && len(conflicts) > 0 {
+		return fmt.Errorf("%d tools already exist, nothing was imported (use --on-conflict skip or overwrite):\n  %s", len(conflicts), strings.Join(conflicts, "\n  "))
+	}
+
+	suffix := ""
+	if dryRun {
+		suffix = " (dry run)"
+	}
+	counts := make(map[string]int)
+	failed := 0
+	for i, m := range manifests {
+		result, err := importManifest(toolClient, m, existing[i], onConflict, dryRun)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", m.source, err)
+			failed++
+			continue
+		}
+		counts[result]++
+		fmt.Printf("%s %s%s\n", describeManifest(m), result, suffix)
+	}
+
+	fmt.Printf("\n%d created, %d overwritten, %d unchanged, %d skipped%s\n",
+		counts[applyCreated], counts[importOverwritten], counts[applyUnchanged], counts[importSkipped], suffix)
+	if failed > 0 {
+		return fmt.Errorf("failed to import %d of %d tools", failed, len(manifests))
+	}
+	return nil
+}
+
+// findExisting returns the existing object of every manifest, or nil if there is none, and
+// the names of the existing tools that differ from their manifest. Tools that already match
+// the bundle are no conflict.
+func findExisting(toolClient *k8s.ToolClient, manifests []manifest) ([]*unstructured.Unstructured, []string, error) {
+	existing := make([]*unstructured.Unstructured, len(manifests))
+	var conflicts []string
+	for i, m := range manifests {
+		object, err := toolClient.GetObject(context.TODO(), m.object.GetKind(), m.object.GetNamespace(), m.object.GetName())
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get %s: %w", describeManifest(m), err)
+		}
+		existing[i] = object
+		if manifestChanges(object, m.defaulted) {
+			conflicts = append(conflicts, describeManifest(m))
+		}
+	}
+	return existing, conflicts, nil
+}
+
+// importManifest creates a checked manifest, or handles the existing tool by the conflict policy
+func importManifest(toolClient *k8s.ToolClient, m manifest, existing *unstructured.Unstructured, onConflict string, dryRun bool) (string, error) {
+	switch {
+	case existing == nil:
+		if dryRun {
+			return applyCreated, nil
+		}
+		created, err := toolClient.CreateObject(context.TODO(), m.object)
+		if err != nil {
+			return "", fmt.Errorf("failed to create %s: %w", describeManifest(m), err)
+		}
+		if err := saveObjectRevision(toolClient, created, "rapt import"); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s was imported, but its revision was not recorded: %v\n", describeManifest(m), err)
+		}
+		recordArgumentCount(toolClient, created.GetKind(), created.GetNamespace(), created.GetName())
+		return applyCreated, nil
+	case !manifestChanges(existing, m.defaulted):
+		return applyUnchanged, nil
+	case onConflict == ConflictSkip:
+		return importSkipped, nil
+	case dryRun:
+		return importOverwritten, nil
+	}
+
+	object := m.object.DeepCopy()
+	object.SetUID(existing.GetUID())
+	object.SetResourceVersion(existing.GetResourceVersion())
+	revision := existing.GetAnnotations()[v1alpha1.RevisionAnnotation]
+	if existing.GetKind() == v1alpha1.ToolKind {
+		// Keep changes made outside of rapt, so that they can be restored later
+		if tool, err := v1alpha1.ToolFromUnstructured(existing); err == nil {
+			saved, err := saveToolRevision(toolClient, tool, "changed outside of rapt")
+			if err != nil {
+				return "", err
+			}
+			object.SetResourceVersion(saved.ResourceVersion)
+			revision = saved.Annotations[v1alpha1.RevisionAnnotation]
+		}
+	}
+	// The revision annotation keeps the history of the existing tool going
+	if revision != "" {
+		annotations := object.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[v1alpha1.RevisionAnnotation] = revision
+		object.SetAnnotations(annotations)
+	}
+
+	updated, err := toolClient.UpdateObject(context.TODO(), object)
+	if err != nil {
+		return "", fmt.Errorf("failed to overwrite %s: %w", describeManifest(m), err)
+	}
+	if err := saveObjectRevision(toolClient, updated, "rapt import"); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %s was imported, but its revision was not recorded: %v\n", describeManifest(m), err)
+	}
+	recordArgumentCount(toolClient, updated.GetKind(), updated.GetNamespace(), updated.GetName())
+	return importOverwritten, nil
+}
+
+// cleanObject removes the namespace, the status and everything else that ties an object
+// to the cluster it was read from
+func cleanObject(object *unstructured.Unstructured) {
+	for _, field := range serverMetadata {
+		unstructured.RemoveNestedField(object.Object, "metadata", field)
+	}
+	unstructured.RemoveNestedField(object.Object, "status")
+
+	annotations := object.GetAnnotations()
+	for _, annotation := range clusterAnnotations {
+		delete(annotations, annotation)
+	}
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	object.SetAnnotations(annotations)
+}
+
+// warnMissingBases warns about exported tools that extend a tool which is not part of the bundle
+func warnMissingBases(w io.Writer, objects []unstructured.Unstructured) {
+	exported := make(map[string]bool, len(objects))
+	for _, object := range objects {
+		exported[object.GetKind()+"/"+object.GetName()] = true
+	}
+	for _, object := range objects {
+		name, _, _ := unstructured.NestedString(object.Object, "spec", "extends", "name")
+		if name == "" {
+			continue
+		}
+		kind, _, _ := unstructured.NestedString(object.Object, "spec", "extends", "kind")
+		if kind == "" {
+			kind = v1alpha1.ToolKind
+		}
+		if !exported[kind+"/"+name] {
+			fmt.Fprintf(w, "Warning: %s extends %s/%s, which is not part of the bundle and must exist where it is imported\n",
+				describeManifest(manifest{object: &object}), kind, name)
+		}
+	}
+}
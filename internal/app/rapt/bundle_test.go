@@ -0,0 +1,249 @@
+package rapt
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"codeberg.org/lig/rapt/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// checkedManifest returns the manifest of a document, checked for the default namespace
+func checkedManifest(t *testing.T, document string) manifest {
+	t.Helper()
+	validators, err := loadSchemaValidators()
+	if err != nil {
+		t.Fatalf("loadSchemaValidators() error = %v", err)
+	}
+	m := manifest{source: "bundle.yaml (document 1)", object: decodeManifest(t, document)}
+	cleanObject(m.object)
+	if errs := checkManifest(&m, validators, "", "default"); len(errs) > 0 {
+		t.Fatalf("checkManifest() = %v", errs)
+	}
+	return m
+}
+
+func TestCleanObject(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		want     string
+	}{
+		{
+			name: "server fields",
+			document: `apiVersion: rapt.dev/v1alpha1
+kind: Tool
+metadata:
+  name: report
+  namespace: staging
+  uid: 11111111-aaaa
+  resourceVersion: "42"
+  generation: 3
+  creationTimestamp: "2024-05-01T10:00:00Z"
+  managedFields:
+    - manager: rapt
+  ownerReferences:
+    - kind: Other
+      name: owner
+  labels:
+    team: ops
+spec:
+  jobTemplate:
+    image: alpine:3.20
+status:
+  lastRun: "2024-05-01T10:00:00Z"
+`,
+			want: "apiVersion: rapt.dev/v1alpha1\nkind: Tool\nmetadata:\n  labels:\n    team: ops\n  name: report\nspec:\n  jobTemplate:\n    image: alpine:3.20\n",
+		},
+		{
+			name:     "cluster annotations",
+			document: "apiVersion: rapt.dev/v1alpha1\nkind: Tool\nmetadata:\n  name: report\n  annotations:\n    rapt.dev/revision: \"3\"\n    kubectl.kubernetes.io/last-applied-configuration: \"{}\"\n    owner: ops\n",
+			want:     "apiVersion: rapt.dev/v1alpha1\nkind: Tool\nmetadata:\n  annotations:\n    owner: ops\n  name: report\n",
+		},
+		{
+			name:     "only cluster annotations",
+			document: "apiVersion: rapt.dev/v1alpha1\nkind: Tool\nmetadata:\n  name: report\n  annotations:\n    rapt.dev/revision: \"3\"\n",
+			want:     "apiVersion: rapt.dev/v1alpha1\nkind: Tool\nmetadata:\n  name: report\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			object := decodeManifest(t, tt.document)
+			cleanObject(object)
+			if want := decodeManifest(t, tt.want); !equalJSON(object.Object, want.Object) {
+				t.Errorf("cleanObject() = %v, want %v", object.Object, want.Object)
+			}
+		})
+	}
+}
+
+func TestFindExisting(t *testing.T) {
+	same := newTestTool("same", "11111111-aaaa")
+	changed := newTestTool("changed", "22222222-bbbb")
+	toolClient := newFakeToolClient(t, same, changed)
+
+	manifests := []manifest{
+		checkedManifest(t, "apiVersion: rapt.dev/v1alpha1\nkind: Tool\nmetadata:\n  name: new\nspec:\n  jobTemplate:\n    image: alpine:3.20\n"),
+		checkedManifest(t, "apiVersion: rapt.dev/v1alpha1\nkind: Tool\nmetadata:\n  name: same\nspec:\n  jobTemplate:\n    image: alpine:3.20\n"),
+		checkedManifest(t, "apiVersion: rapt.dev/v1alpha1\nkind: Tool\nmetadata:\n  name: changed\nspec:\n  jobTemplate:\n    image: alpine:3.21\n"),
+	}
+	existing, conflicts, err := findExisting(toolClient, manifests)
+	if err != nil {
+		t.Fatalf("findExisting() error = %v", err)
+	}
+	if existing[0] != nil || existing[1] == nil || existing[2] == nil {
+		t.Errorf("existing = %v, want the same and changed tools only", existing)
+	}
+	// A tool that already matches the bundle does not fail --on-conflict fail
+	if want := []string{"tool.rapt.dev/changed"}; !slices.Equal(conflicts, want) {
+		t.Errorf("conflicts = %q, want %q", conflicts, want)
+	}
+}
+
+func TestImportManifest(t *testing.T) {
+	const bundled = "apiVersion: rapt.dev/v1alpha1\nkind: Tool\nmetadata:\n  name: report\nspec:\n  jobTemplate:\n    image: alpine:3.21\n"
+
+	tests := []struct {
+		name       string
+		existing   bool
+		image      string
+		onConflict string
+		dryRun     bool
+		want       string
+		// wantImage is the image of the tool in the cluster afterwards
+		wantImage string
+	}{
+		{name: "new tool", onConflict: ConflictFail, want: applyCreated, wantImage: "alpine:3.21"},
+		{name: "new tool dry run", onConflict: ConflictFail, dryRun: true, want: applyCreated},
+		{name: "unchanged with skip", existing: true, image: "alpine:3.21", onConflict: ConflictSkip, want: applyUnchanged, wantImage: "alpine:3.21"},
+		{name: "unchanged with overwrite", existing: true, image: "alpine:3.21", onConflict: ConflictOverwrite, want: applyUnchanged, wantImage: "alpine:3.21"},
+		{name: "skip", existing: true, image: "alpine:3.20", onConflict: ConflictSkip, want: importSkipped, wantImage: "alpine:3.20"},
+		{name: "overwrite", existing: true, image: "alpine:3.20", onConflict: ConflictOverwrite, want: importOverwritten, wantImage: "alpine:3.21"},
+		{name: "overwrite dry run", existing: true, image: "alpine:3.20", onConflict: ConflictOverwrite, dryRun: true, want: importOverwritten, wantImage: "alpine:3.20"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var objects []convertible
+			if tt.existing {
+				tool := newTestTool("report", "11111111-aaaa")
+				tool.Spec.JobTemplate.Image = tt.image
+				objects = append(objects, tool)
+			}
+			toolClient := newFakeToolClient(t, objects...)
+
+			m := checkedManifest(t, bundled)
+			var existing *unstructured.Unstructured
+			if tt.existing {
+				var err error
+				if existing, err = toolClient.GetObject(context.TODO(), v1alpha1.ToolKind, "default", "report"); err != nil {
+					t.Fatalf("GetObject() error = %v", err)
+				}
+			}
+
+			got, err := importManifest(toolClient, m, existing, tt.onConflict, tt.dryRun)
+			if err != nil {
+				t.Fatalf("importManifest() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("importManifest() = %q, want %q", got, tt.want)
+			}
+
+			tool, err := toolClient.Get(context.TODO(), "default", "report")
+			if tt.wantImage == "" {
+				if err == nil {
+					t.Errorf("tool was created in a dry run")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if tool.Spec.JobTemplate.Image != tt.wantImage {
+				t.Errorf("image = %q, want %q", tool.Spec.JobTemplate.Image, tt.wantImage)
+			}
+		})
+	}
+}
+
+func TestImportManifestOverwriteKeepsHistory(t *testing.T) {
+	tool := newTestTool("report", "11111111-aaaa")
+	tool.Annotations = map[string]string{v1alpha1.RevisionAnnotation: "1"}
+	toolClient := newFakeToolClient(t, tool, v1alpha1.NewToolRevision(tool, 1, "rapt add"))
+	existing, err := toolClient.GetObject(context.TODO(), v1alpha1.ToolKind, "default", "report")
+	if err != nil {
+		t.Fatalf("GetObject() error = %v", err)
+	}
+
+	m := checkedManifest(t, "apiVersion: rapt.dev/v1alpha1\nkind: Tool\nmetadata:\n  name: report\nspec:\n  jobTemplate:\n    image: alpine:3.21\n")
+	if _, err := importManifest(toolClient, m, existing, ConflictOverwrite, false); err != nil {
+		t.Fatalf("importManifest() error = %v", err)
+	}
+
+	updated, err := toolClient.Get(context.TODO(), "default", "report")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if updated.CurrentRevision() != 2 {
+		t.Errorf("current revision = %d, want 2", updated.CurrentRevision())
+	}
+	revisions, err := toolClient.ListRevisions(context.TODO(), updated)
+	if err != nil {
+		t.Fatalf("ListRevisions() error = %v", err)
+	}
+	if len(revisions) != 2 || revisions[1].Spec.JobTemplate.Image != "alpine:3.21" {
+		t.Errorf("revisions = %+v, want the imported spec recorded as revision 2", revisions)
+	}
+}
+
+func TestWarnMissingBases(t *testing.T) {
+	tests := []struct {
+		name      string
+		documents []string
+		want      []string
+	}{
+		{
+			name:      "no extends",
+			documents: []string{"kind: Tool\nmetadata:\n  name: report\n"},
+		},
+		{
+			name: "base in the bundle",
+			documents: []string{
+				"kind: Tool\nmetadata:\n  name: report\n",
+				"kind: Tool\nmetadata:\n  name: report-json\nspec:\n  extends:\n    name: report\n",
+			},
+		},
+		{
+			name:      "base missing",
+			documents: []string{"kind: Tool\nmetadata:\n  name: report-json\nspec:\n  extends:\n    name: report\n"},
+			want:      []string{"Warning: tool.rapt.dev/report-json extends Tool/report, which is not part of the bundle and must exist where it is imported"},
+		},
+		{
+			name: "base of another kind",
+			documents: []string{
+				"kind: Tool\nmetadata:\n  name: report\n",
+				"kind: Tool\nmetadata:\n  name: report-json\nspec:\n  extends:\n    kind: ClusterTool\n    name: report\n",
+			},
+			want: []string{"Warning: tool.rapt.dev/report-json extends ClusterTool/report, which is not part of the bundle and must exist where it is imported"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var objects []unstructured.Unstructured
+			for _, document := range tt.documents {
+				objects = append(objects, *decodeManifest(t, "apiVersion: rapt.dev/v1alpha1\n"+document))
+			}
+			var out bytes.Buffer
+			warnMissingBases(&out, objects)
+			var got []string
+			if out.Len() > 0 {
+				got = strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("warnMissingBases() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
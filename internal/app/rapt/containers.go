@@ -0,0 +1,54 @@
+package rapt
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"codeberg.org/lig/rapt/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// nativeSidecarMinorVersion is the first Kubernetes 1.x release with native sidecars enabled by default
+const nativeSidecarMinorVersion = 29
+
+// supportsNativeSidecars reports whether the cluster runs init containers with restartPolicy Always as sidecars
+func supportsNativeSidecars(k8sClient kubernetes.Interface) bool {
+	version, err := k8sClient.Discovery().ServerVersion()
+	if err != nil {
+		return false
+	}
+	major, err := strconv.Atoi(strings.TrimSuffix(version.Major, "+"))
+	if err != nil {
+		return false
+	}
+	minor, err := strconv.Atoi(strings.TrimSuffix(version.Minor, "+"))
+	if err != nil {
+		return false
+	}
+	return major > 1 || (major == 1 && minor >= nativeSidecarMinorVersion)
+}
+
+// addExtraContainers adds the tool's init containers and sidecars to a pod spec.
+// Sidecars become native sidecars when the cluster supports them and regular containers otherwise.
+func addExtraContainers(podSpec *corev1.PodSpec, jobTemplate v1alpha1.JobTemplate, nativeSidecars bool) {
+	for _, container := range jobTemplate.InitContainers {
+		podSpec.InitContainers = append(podSpec.InitContainers, *container.DeepCopy())
+	}
+
+	if len(jobTemplate.Sidecars) > 0 && !nativeSidecars {
+		fmt.Fprintln(os.Stderr, "Warning: the cluster does not support native sidecars, the job only completes once all sidecars exit")
+	}
+	for _, container := range jobTemplate.Sidecars {
+		sidecar := container.DeepCopy()
+		if nativeSidecars {
+			restartPolicy := corev1.ContainerRestartPolicyAlways
+			sidecar.RestartPolicy = &restartPolicy
+			podSpec.InitContainers = append(podSpec.InitContainers, *sidecar)
+		} else {
+			podSpec.Containers = append(podSpec.Containers, *sidecar)
+		}
+	}
+}
@@ -6,22 +6,25 @@ import (
 	"strings"
 
 	"codeberg.org/lig/rapt/internal/k8s"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	"github.com/AlecAivazis/survey/v2"
 )
 
-// DeleteTools deletes one or more tool definitions from the cluster
-func DeleteTools(namespace string, toolNames []string, force bool) error {
-	// Initialize dynamic client
-	dynClient, err := k8s.InitDynamicClient(namespace)
+// DeleteTools deletes one or more tool definitions from the cluster.
+// With cluster set, the names refer to ClusterTools.
+func DeleteTools(namespace string, toolNames []string, cluster, force bool) error {
+	// Initialize tool client
+	toolClient, err := k8s.InitToolClient(namespace)
 	if err != nil {
-		return fmt.Errorf("failed to initialize dynamic client: %w", err)
+		return fmt.Errorf("failed to initialize tool client: %w", err)
 	}
 
 	// Confirm deletion unless forced
 	if !force {
-		confirmMessage := fmt.Sprintf("Are you sure you want to delete tool(s): %s?", strings.Join(toolNames, ", "))
+		kind := "tool(s)"
+		if cluster {
+			kind = "cluster tool(s)"
+		}
+		confirmMessage := fmt.Sprintf("Are you sure you want to delete %s: %s?", kind, strings.Join(toolNames, ", "))
 		prompt := &survey.Confirm{
 			Message: confirmMessage,
 			Default: false,
@@ -38,17 +41,15 @@ func DeleteTools(namespace string, toolNames []string, force bool) error {
 	}
 
 	// Delete each tool
-	gvr := schema.GroupVersionResource{
-		Group:    "rapt.dev",
-		Version:  "v1alpha1",
-		Resource: "tools",
-	}
-
 	var deletedTools []string
 	var failedTools []string
 
 	for _, toolName := range toolNames {
-		err := dynClient.Resource(gvr).Namespace(namespace).Delete(context.TODO(), toolName, metav1.DeleteOptions{})
+		if cluster {
+			err = toolClient.DeleteClusterTool(context.TODO(), toolName)
+		} else {
+			err = toolClient.Delete(context.TODO(), namespace, toolName)
+		}
 		if err != nil {
 			failedTools = append(failedTools, toolName)
 			fmt.Printf("Failed to delete tool '%s': %v\n", toolName, err)
@@ -71,35 +72,23 @@ func DeleteTools(namespace string, toolNames []string, force bool) error {
 
 // DeleteAllTools deletes all tool definitions from the namespace
 func DeleteAllTools(namespace string, force bool) error {
-	// Initialize dynamic client
-	dynClient, err := k8s.InitDynamicClient(namespace)
+	// Initialize tool client
+	toolClient, err := k8s.InitToolClient(namespace)
 	if err != nil {
-		return fmt.Errorf("failed to initialize dynamic client: %w", err)
+		return fmt.Errorf("failed to initialize tool client: %w", err)
 	}
 
 	// Get all tools first
-	gvr := schema.GroupVersionResource{
-		Group:    "rapt.dev",
-		Version:  "v1alpha1",
-		Resource: "tools",
-	}
-
-	tools, err := dynClient.Resource(gvr).Namespace(namespace).List(context.TODO(), metav1.ListOptions{})
+	toolNames, err := toolClient.ListNames(context.TODO(), namespace)
 	if err != nil {
 		return fmt.Errorf("failed to list tools: %w", err)
 	}
 
-	if len(tools.Items) == 0 {
+	if len(toolNames) == 0 {
 		fmt.Printf("No tools found in namespace '%s'.\n", namespace)
 		return nil
 	}
 
-	// Extract tool names
-	toolNames := make([]string, len(tools.Items))
-	for i, tool := range tools.Items {
-		toolNames[i] = tool.GetName()
-	}
-
 	// Confirm deletion unless forced
 	if !force {
 		confirmMessage := fmt.Sprintf("Are you sure you want to delete ALL %d tool(s) in namespace '%s'?\nTools: %s", 
@@ -124,7 +113,7 @@ func DeleteAllTools(namespace string, force bool) error {
 	var failedTools []string
 
 	for _, toolName := range toolNames {
-		err := dynClient.Resource(gvr).Namespace(namespace).Delete(context.TODO(), toolName, metav1.DeleteOptions{})
+		err := toolClient.Delete(context.TODO(), namespace, toolName)
 		if err != nil {
 			failedTools = append(failedTools, toolName)
 			fmt.Printf("Failed to delete tool '%s': %v\n", toolName, err)
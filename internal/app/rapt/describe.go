@@ -4,31 +4,56 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"text/tabwriter"
 
+	"codeberg.org/lig/rapt/api/v1alpha1"
 	"codeberg.org/lig/rapt/internal/k8s"
+	"github.com/kballard/go-shellquote"
 	yamlv2 "sigs.k8s.io/yaml"
 )
 
-// DescribeTool shows detailed information about a specific tool
-func DescribeTool(namespace, toolName, outputFormat string) error {
-	// Initialize dynamic client
-	dynClient, err := k8s.InitDynamicClient(namespace)
+// DescribeTool shows detailed information about a specific tool.
+// args are used to show the command line the tool runs with; required arguments
+// that are not given are shown as <name>. With resolved set, the effective spec
+// merged with the tools it extends is shown instead of the tool's own spec.
+func DescribeTool(namespace, toolName, outputFormat string, args map[string]string, resolved bool) error {
+	// Initialize tool client
+	toolClient, err := k8s.InitToolClient(namespace)
 	if err != nil {
-		return fmt.Errorf("failed to initialize dynamic client: %w", err)
+		return fmt.Errorf("failed to initialize tool client: %w", err)
 	}
 
 	// Get the tool definition
-	tool, err := getToolDefinition(dynClient, namespace, toolName)
+	tool, err := getToolDefinition(toolClient, namespace, toolName)
 	if err != nil {
 		return fmt.Errorf("failed to get tool definition: %w", err)
 	}
 
-	// Convert to ToolInfo for display
-	toolInfo, err := convertToToolInfo(tool)
+	// The command line always comes from the effective spec
+	effective, err := resolveTool(toolClient, tool)
 	if err != nil {
-		return fmt.Errorf("failed to convert tool: %w", err)
+		if resolved {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	extends := tool.Spec.Extends
+	if resolved {
+		tool = effective
+	}
+
+	// Convert to ToolInfo for display
+	toolInfo := convertToToolInfo(tool)
+	if extends != nil {
+		toolInfo.Extends = extends.String()
+	}
+	if effective != nil {
+		toolInfo.CommandLine, err = describeCommandLine(effective, args)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Output based on format
@@ -48,7 +73,13 @@ func DescribeTool(namespace, toolName, outputFormat string) error {
 // outputToolTable outputs tool information in table format
 func outputToolTable(tool ToolInfo) error {
 	fmt.Printf("Name:        %s\n", tool.Name)
-	fmt.Printf("Namespace:   %s\n", tool.Namespace)
+	if tool.Namespace != "" {
+		fmt.Printf("Namespace:   %s\n", tool.Namespace)
+	}
+	fmt.Printf("Source:      %s\n", tool.Source)
+	if tool.Extends != "" {
+		fmt.Printf("Extends:     %s\n", tool.Extends)
+	}
 	fmt.Printf("Created:     %s\n", tool.Created.Format("2006-01-02 15:04:05"))
 	fmt.Printf("Image:       %s\n", tool.Image)
 	
@@ -60,10 +91,34 @@ func outputToolTable(tool ToolInfo) error {
 		fmt.Printf("Command:     %s\n", strings.Join(tool.Command, " "))
 	}
 
+	if len(tool.Args) > 0 {
+		fmt.Printf("Args:        %s\n", strings.Join(tool.Args, " "))
+	}
+
+	if tool.CommandLine != "" {
+		fmt.Printf("Command Line: %s\n", tool.CommandLine)
+	}
+
+	if tool.ServiceAccount != "" {
+		fmt.Printf("Service Account: %s\n", tool.ServiceAccount)
+	}
+	if tool.SecurityProfile != "" {
+		fmt.Printf("Security Profile: %s\n", tool.SecurityProfile)
+	}
+
+	if tool.Status != nil {
+		fmt.Printf("Last Run:    %s", describeLastRun(tool.Status))
+		if tool.Status.LastJobName != "" {
+			fmt.Printf(" (job %s)", tool.Status.LastJobName)
+		}
+		fmt.Println()
+		fmt.Printf("Runs:        %d succeeded, %d failed\n", tool.Status.SucceededRuns, tool.Status.FailedRuns)
+	}
+
 	if len(tool.Arguments) > 0 {
 		fmt.Println("\nArguments:")
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-		fmt.Fprintln(w, "NAME\tDESCRIPTION\tREQUIRED\tDEFAULT")
+		fmt.Fprintln(w, "NAME\tTYPE\tDESCRIPTION\tREQUIRED\tDEFAULT\tCONSTRAINTS")
 		for _, arg := range tool.Arguments {
 			required := "No"
 			if arg.Required {
@@ -73,7 +128,47 @@ func outputToolTable(tool ToolInfo) error {
 			if arg.Default != "" {
 				defaultValue = arg.Default
 			}
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", arg.Name, arg.Description, required, defaultValue)
+			constraints := "-"
+			if arg.Constraints != "" {
+				constraints = arg.Constraints
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", arg.Name, arg.Type, arg.Description, required, defaultValue, constraints)
+		}
+		w.Flush()
+	}
+
+	if tool.Resources != nil {
+		fmt.Println("\nResources:")
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "RESOURCE\tREQUEST\tLIMIT")
+		for _, name := range resourceNames(tool.Resources) {
+			request := "-"
+			if value, ok := tool.Resources.Requests[name]; ok {
+				request = value
+			}
+			limit := "-"
+			if value, ok := tool.Resources.Limits[name]; ok {
+				limit = value
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", name, request, limit)
+		}
+		w.Flush()
+	}
+
+	if len(tool.Volumes) > 0 {
+		fmt.Println("\nVolumes:")
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tSOURCE\tMOUNT PATH\tREAD ONLY")
+		for _, volume := range tool.Volumes {
+			mountPath := "-"
+			if volume.MountPath != "" {
+				mountPath = volume.MountPath
+			}
+			readOnly := "No"
+			if volume.ReadOnly {
+				readOnly = "Yes"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", volume.Name, volume.Source, mountPath, readOnly)
 		}
 		w.Flush()
 	}
@@ -83,7 +178,29 @@ func outputToolTable(tool ToolInfo) error {
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 		fmt.Fprintln(w, "NAME\tVALUE")
 		for _, env := range tool.Environment {
-			fmt.Fprintf(w, "%s\t%s\n", env.Name, env.Value)
+			value := env.Value
+			if env.Source != "" {
+				value = fmt.Sprintf("<%s>", env.Source)
+			}
+			fmt.Fprintf(w, "%s\t%s\n", env.Name, value)
+		}
+		w.Flush()
+	}
+
+	if len(tool.EnvFrom) > 0 {
+		fmt.Println("\nEnvironment From:")
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "KIND\tNAME\tPREFIX\tOPTIONAL")
+		for _, source := range tool.EnvFrom {
+			prefix := "-"
+			if source.Prefix != "" {
+				prefix = source.Prefix
+			}
+			optional := "No"
+			if source.Optional {
+				optional = "Yes"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", source.Kind, source.Name, prefix, optional)
 		}
 		w.Flush()
 	}
@@ -91,6 +208,61 @@ func outputToolTable(tool ToolInfo) error {
 	return nil
 }
 
+// describeCommandLine renders the command line the tool runs with for the given arguments
+func describeCommandLine(tool *v1alpha1.Tool, args map[string]string) (string, error) {
+	arguments := resolveArguments(tool, args)
+	values := arguments.Values
+	missing := arguments.Missing
+	for _, name := range missing {
+		values[name] = fmt.Sprintf("<%s>", name)
+	}
+	arguments.Missing = nil
+	if err := arguments.Err(); err != nil {
+		return "", err
+	}
+
+	rendered, err := renderInvocation(tool, values)
+	if err != nil {
+		return "", err
+	}
+
+	var parts []string
+	for _, env := range rendered.Env {
+		parts = append(parts, shellquote.Join(env.Name+"="+env.Value))
+	}
+	if len(rendered.Command) > 0 {
+		parts = append(parts, shellquote.Join(rendered.Command...))
+	} else {
+		parts = append(parts, "<entrypoint>")
+	}
+	if len(rendered.Args) > 0 {
+		parts = append(parts, shellquote.Join(rendered.Args...))
+	}
+	commandLine := strings.Join(parts, " ")
+
+	// Show placeholders for missing arguments unquoted, they are not literal values
+	for _, name := range missing {
+		placeholder := fmt.Sprintf("<%s>", name)
+		commandLine = strings.ReplaceAll(commandLine, shellquote.Join(placeholder), placeholder)
+	}
+	return commandLine, nil
+}
+
+// resourceNames returns the sorted names of all requested or limited resources
+func resourceNames(resources *ToolResources) []string {
+	var names []string
+	for name := range resources.Requests {
+		names = append(names, name)
+	}
+	for name := range resources.Limits {
+		if _, ok := resources.Requests[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
 // outputToolJSON outputs tool information in JSON format
 func outputToolJSON(tool ToolInfo) error {
 	encoder := json.NewEncoder(os.Stdout)
@@ -0,0 +1,224 @@
+package rapt
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"codeberg.org/lig/rapt/api/v1alpha1"
+	"codeberg.org/lig/rapt/internal/k8s"
+	"github.com/kballard/go-shellquote"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	yamlv2 "sigs.k8s.io/yaml"
+)
+
+// defaultEditor is used when $EDITOR is not set
+const defaultEditor = "vi"
+
+// editHeader is shown above the tool in the editor
+const editHeader = `# Please edit the tool below. Lines beginning with a '#' will be ignored,
+# and an empty file will cancel the edit. If an error occurs while saving,
+# this file will be reopened with the relevant failures.
+#
+`
+
+// EditTool opens the YAML of a tool in $EDITOR and saves the edited tool.
+// The edited tool is checked like a manifest of rapt apply and the editor is reopened with
+// the problems until it is valid or the edit is cancelled. The tool is only replaced if
+// nobody else changed it while it was edited. With cluster set, the name refers to a ClusterTool.
+func EditTool(namespace, toolName string, cluster bool) error {
+	toolClient, err := k8s.InitToolClient(namespace)
+	if err != nil {
+		return fmt.Errorf("failed to initialize tool client: %w", err)
+	}
+	validators, err := loadSchemaValidators()
+	if err != nil {
+		return err
+	}
+
+	original, err := getEditableObject(toolClient, namespace, toolName, cluster)
+	if err != nil {
+		return err
+	}
+	editable := original.DeepCopy()
+	unstructured.RemoveNestedField(editable.Object, "metadata", "managedFields")
+	unstructured.RemoveNestedField(editable.Object, "status")
+	content, err := yamlv2.Marshal(editable.Object)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tool %s: %w", toolName, err)
+	}
+
+	file, err := os.CreateTemp("", "rapt-edit-"+toolName+"-*.yaml")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	path := file.Name()
+	file.Close()
+
+	header := editHeader
+	var previous []byte
+	for {
+		if err := os.WriteFile(path, append([]byte(header), content...), 0o600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		if err := runEditor(path); err != nil {
+			os.Remove(path)
+			return err
+		}
+		saved, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		edited := stripComments(saved)
+		if len(bytes.TrimSpace(edited)) == 0 {
+			os.Remove(path)
+			fmt.Println("Edit cancelled, the file was empty.")
+			return nil
+		}
+		if bytes.Equal(edited, stripComments(content)) && previous == nil {
+			os.Remove(path)
+			fmt.Println("Edit cancelled, no changes made.")
+			return nil
+		}
+		if previous != nil && bytes.Equal(edited, previous) {
+			return fmt.Errorf("edit cancelled, the invalid changes were saved again without a fix; they are kept in %s", path)
+		}
+
+		updated, err := saveEditedObject(toolClient, original, edited, validators)
+		if apierrors.IsConflict(err) {
+			return fmt.Errorf("tool '%s' was changed by someone else while it was edited; your changes are kept in %s, run 'rapt edit %s' again to start from the current version", toolName, path, toolName)
+		}
+		if err != nil {
+			header = editHeader + commentLines(err.Error()) + "#\n"
+			content = edited
+			previous = edited
+			continue
+		}
+
+		os.Remove(path)
+		m := manifest{object: updated}
+		if appliedResult(original, updated) == applyUnchanged {
+			fmt.Printf("%s unchanged\n", describeManifest(m))
+			return nil
+		}
+		if err := saveObjectRevision(toolClient, updated, "rapt edit"); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: tool '%s' was edited, but its revision was not recorded: %v\n", toolName, err)
+		}
+		recordArgumentCount(toolClient, updated.GetKind(), updated.GetNamespace(), updated.GetName())
+		fmt.Printf("%s edited\n", describeManifest(m))
+		return nil
+	}
+}
+
+// getEditableObject returns the Tool or ClusterTool to edit. Changes made to a valid Tool
+// outside of rapt are recorded as a revision first, so that they can be restored later.
+func getEditableObject(toolClient *k8s.ToolClient, namespace, toolName string, cluster bool) (*unstructured.Unstructured, error) {
+	kind := v1alpha1.ToolKind
+	if cluster {
+		kind = v1alpha1.ClusterToolKind
+		namespace = ""
+	}
+
+	object, err := toolClient.GetObject(context.TODO(), kind, namespace, toolName)
+	if apierrors.IsNotFound(err) {
+		if cluster {
+			return nil, fmt.Errorf("cluster tool '%s' not found", toolName)
+		}
+		return nil, fmt.Errorf("tool '%s' not found in namespace '%s'", toolName, namespace)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tool definition: %w", err)
+	}
+	if cluster {
+		return object, nil
+	}
+
+	tool, err := v1alpha1.ToolFromUnstructured(object)
+	if err != nil {
+		// An invalid tool can still be fixed by editing it, it just has no revision to record
+		return object, nil
+	}
+	saved, err := saveToolRevision(toolClient, tool, "changed outside of rapt")
+	if err != nil {
+		return nil, err
+	}
+	if saved == tool {
+		return object, nil
+	}
+	object, err = toolClient.GetObject(context.TODO(), kind, namespace, toolName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tool definition: %w", err)
+	}
+	return object, nil
+}
+
+// saveEditedObject checks the edited YAML of a tool and replaces the original with it.
+// The resourceVersion of the original is kept, so that the update fails with a conflict
+// if the tool was changed since it was read.
+func saveEditedObject(toolClient *k8s.ToolClient, original *unstructured.Unstructured, edited []byte, validators map[string]*k8s.SchemaValidator) (*unstructured.Unstructured, error) {
+	manifests, err := readDocuments("edited tool", bytes.NewReader(edited))
+	if err != nil {
+		return nil, err
+	}
+	if len(manifests) != 1 {
+		return nil, fmt.Errorf("expected exactly one tool, found %d", len(manifests))
+	}
+
+	m := manifests[0]
+	if errs := checkManifest(&m, validators, original.GetNamespace(), original.GetNamespace()); len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	if m.object.GetKind() != original.GetKind() || m.object.GetName() != original.GetName() {
+		return nil, fmt.Errorf("the kind and name of a tool cannot be changed, expected %s %s", original.GetKind(), original.GetName())
+	}
+
+	m.object.SetResourceVersion(original.GetResourceVersion())
+	return toolClient.UpdateObject(context.TODO(), m.object)
+}
+
+// runEditor opens a file in the editor named by $EDITOR, which may include arguments
+func runEditor(path string) error {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = defaultEditor
+	}
+	args, err := shellquote.Split(editor)
+	if err != nil || len(args) == 0 {
+		return fmt.Errorf("invalid $EDITOR %q", editor)
+	}
+
+	cmd := exec.Command(args[0], append(args[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run editor %s: %w", editor, err)
+	}
+	return nil
+}
+
+// stripComments removes the lines that begin with a '#'
+func stripComments(content []byte) []byte {
+	var kept []string
+	for _, line := range strings.SplitAfter(string(content), "\n") {
+		if !strings.HasPrefix(line, "#") {
+			kept = append(kept, line)
+		}
+	}
+	return []byte(strings.Join(kept, ""))
+}
+
+// commentLines turns every line of text into a comment
+func commentLines(text string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		b.WriteString("# " + line + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,121 @@
+package rapt
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"codeberg.org/lig/rapt/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestStripComments(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{name: "no comments", content: "kind: Tool\nmetadata:\n  name: echo\n", want: "kind: Tool\nmetadata:\n  name: echo\n"},
+		{name: "header", content: editHeader + "kind: Tool\n", want: "kind: Tool\n"},
+		{name: "indented and trailing comments are kept", content: "kind: Tool # the kind\n  # indented\n", want: "kind: Tool # the kind\n  # indented\n"},
+		{name: "last line without newline", content: "kind: Tool\n# error", want: "kind: Tool\n"},
+		{name: "comment in a block scalar", content: "help: |\n  text\n# not part of it\n", want: "help: |\n  text\n"},
+		{name: "only comments", content: "# a\n#\n", want: ""},
+		{name: "empty", content: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(stripComments([]byte(tt.content))); got != tt.want {
+				t.Errorf("stripComments(%q) = %q, want %q", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSaveEditedObject(t *testing.T) {
+	validators, err := loadSchemaValidators()
+	if err != nil {
+		t.Fatalf("loadSchemaValidators() error = %v", err)
+	}
+
+	const edited = "apiVersion: rapt.dev/v1alpha1\nkind: Tool\nmetadata:\n  name: report\n  namespace: default\nspec:\n  jobTemplate:\n    image: alpine:3.21\n"
+	tests := []struct {
+		name    string
+		edited  string
+		wantErr string
+	}{
+		{
+			name:   "valid change",
+			edited: edited,
+		},
+		{
+			name:   "namespace left out",
+			edited: strings.Replace(edited, "  namespace: default\n", "", 1),
+		},
+		{
+			name:    "several documents",
+			edited:  edited + "---\n" + strings.Replace(edited, "report", "other", 1),
+			wantErr: "expected exactly one tool, found 2",
+		},
+		{
+			name:    "no document",
+			edited:  "---\n",
+			wantErr: "expected exactly one tool, found 0",
+		},
+		{
+			name:    "name changed",
+			edited:  strings.Replace(edited, "name: report", "name: other", 1),
+			wantErr: "the kind and name of a tool cannot be changed, expected Tool report",
+		},
+		{
+			name:    "kind changed",
+			edited:  strings.Replace(strings.Replace(edited, "kind: Tool", "kind: ClusterTool", 1), "  namespace: default\n", "", 1),
+			wantErr: "the kind and name of a tool cannot be changed, expected Tool report",
+		},
+		{
+			name:    "namespace changed",
+			edited:  strings.Replace(edited, "namespace: default", "namespace: ops", 1),
+			wantErr: "does not match --namespace default",
+		},
+		{
+			name:    "schema error",
+			edited:  edited + "    imagePullPolicy: Always\n",
+			wantErr: "spec.jobTemplate.imagePullPolicy: unknown field",
+		},
+		{
+			name:    "invalid YAML",
+			edited:  "kind: [Tool\n",
+			wantErr: "invalid YAML",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toolClient := newFakeToolClient(t, newTestTool("report", "11111111-aaaa"))
+			original, err := toolClient.GetObject(context.TODO(), v1alpha1.ToolKind, "default", "report")
+			if err != nil {
+				t.Fatalf("GetObject() error = %v", err)
+			}
+
+			updated, err := saveEditedObject(toolClient, original, []byte(tt.edited), validators)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("saveEditedObject() error = %v, want an error containing %q", err, tt.wantErr)
+				}
+				tool, err := toolClient.Get(context.TODO(), "default", "report")
+				if err != nil {
+					t.Fatalf("Get() error = %v", err)
+				}
+				if tool.Spec.JobTemplate.Image != "alpine:3.20" {
+					t.Errorf("image = %q, want the tool left unchanged", tool.Spec.JobTemplate.Image)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("saveEditedObject() error = %v", err)
+			}
+			if image, _, _ := unstructured.NestedString(updated.Object, "spec", "jobTemplate", "image"); image != "alpine:3.21" {
+				t.Errorf("updated image = %q, want alpine:3.21", image)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package rapt
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"codeberg.org/lig/rapt/api/v1alpha1"
+	"codeberg.org/lig/rapt/internal/k8s"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// getResolvedTool retrieves a tool definition and merges it with the tools it extends
+func getResolvedTool(toolClient *k8s.ToolClient, namespace, toolName string) (*v1alpha1.Tool, error) {
+	tool, err := getToolDefinition(toolClient, namespace, toolName)
+	if err != nil {
+		return nil, err
+	}
+	return resolveTool(toolClient, tool)
+}
+
+// resolveTool returns the effective definition of a tool by merging it with the chain
+// of tools it extends, see v1alpha1.MergeToolSpec. Cyclic chains are reported as errors.
+func resolveTool(toolClient *k8s.ToolClient, tool *v1alpha1.Tool) (*v1alpha1.Tool, error) {
+	if tool.Spec.Extends == nil {
+		return tool, nil
+	}
+
+	chain := []*v1alpha1.Tool{tool}
+	for current := tool; current.Spec.Extends != nil; {
+		base, err := getBaseTool(toolClient, current)
+		if err != nil {
+			return nil, err
+		}
+		if slices.ContainsFunc(chain, func(tool *v1alpha1.Tool) bool { return toolKey(tool) == toolKey(base) }) {
+			return nil, fmt.Errorf("cyclic extends chain: %s", describeChain(append(chain, base)))
+		}
+		chain = append(chain, base)
+		current = base
+	}
+
+	// Merge from the root of the chain down to the tool itself
+	spec := chain[len(chain)-1].Spec
+	for i := len(chain) - 2; i >= 0; i-- {
+		spec = v1alpha1.MergeToolSpec(&spec, &chain[i].Spec)
+	}
+
+	resolved := tool.DeepCopy()
+	resolved.Spec = spec
+	if err := resolved.Validate(); err != nil {
+		return nil, fmt.Errorf("%w (extends chain: %s)", err, describeChain(chain))
+	}
+	return resolved, nil
+}
+
+// getBaseTool retrieves the tool that the given tool extends
+func getBaseTool(toolClient *k8s.ToolClient, tool *v1alpha1.Tool) (*v1alpha1.Tool, error) {
+	ref := tool.Spec.Extends
+
+	var base *v1alpha1.Tool
+	var err error
+	if ref.RefKind() == v1alpha1.ClusterToolKind {
+		var clusterTool *v1alpha1.ClusterTool
+		clusterTool, err = toolClient.GetClusterTool(context.TODO(), ref.Name)
+		if err == nil {
+			base = clusterTool.AsTool()
+		}
+	} else {
+		base, err = toolClient.Get(context.TODO(), tool.Namespace, ref.Name)
+	}
+
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("tool '%s' extends %s, which does not exist", tool.Name, ref)
+		}
+		return nil, fmt.Errorf("failed to get base tool %s of tool '%s': %w", ref, tool.Name, err)
+	}
+	return base, nil
+}
+
+// toolKey identifies a Tool or ClusterTool within the cluster
+func toolKey(tool *v1alpha1.Tool) string {
+	if tool.IsClusterTool() {
+		return v1alpha1.ClusterToolKind + "/" + tool.Name
+	}
+	return v1alpha1.ToolKind + "/" + tool.Namespace + "/" + tool.Name
+}
+
+// describeChain formats an extends chain as Kind/name -> Kind/name
+func describeChain(chain []*v1alpha1.Tool) string {
+	names := make([]string, len(chain))
+	for i, tool := range chain {
+		kind := v1alpha1.ToolKind
+		if tool.IsClusterTool() {
+			kind = v1alpha1.ClusterToolKind
+		}
+		names[i] = kind + "/" + tool.Name
+	}
+	return strings.Join(names, " -> ")
+}
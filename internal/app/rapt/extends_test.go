@@ -0,0 +1,83 @@
+package rapt
+
+import (
+	"strings"
+	"testing"
+
+	"codeberg.org/lig/rapt/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// extendingTool returns a Tool in the default namespace that extends the referenced tool
+func extendingTool(name, kind, base string) *v1alpha1.Tool {
+	tool := newTestTool(name, "")
+	tool.Spec.JobTemplate.Image = ""
+	tool.Spec.Extends = &v1alpha1.ToolReference{Kind: kind, Name: base}
+	return tool
+}
+
+func TestResolveTool(t *testing.T) {
+	platform := &v1alpha1.ClusterTool{
+		ObjectMeta: metav1.ObjectMeta{Name: "platform"},
+		Spec: v1alpha1.ToolSpec{JobTemplate: v1alpha1.JobTemplate{
+			Image: "platform:1",
+			Env:   []corev1.EnvVar{{Name: "LEVEL", Value: "info"}, {Name: "REGION", Value: "eu"}},
+		}},
+	}
+	team := extendingTool("team", v1alpha1.ClusterToolKind, "platform")
+	team.Spec.JobTemplate.Env = []corev1.EnvVar{{Name: "LEVEL", Value: "debug"}}
+	report := extendingTool("report", "", "team")
+	report.Spec.JobTemplate.Image = "report:2"
+	// The base of a tool has to declare the volumes it mounts
+	mountsCache := extendingTool("a", "", "b")
+	mountsCache.Spec.JobTemplate.VolumeMounts = []corev1.VolumeMount{{Name: "cache", MountPath: "/cache"}}
+
+	tests := []struct {
+		name    string
+		tool    *v1alpha1.Tool
+		objects []convertible
+		wantErr string
+	}{
+		{name: "chain", tool: report, objects: []convertible{platform, team, report}},
+		{name: "missing base", tool: extendingTool("orphan", "", "gone"), wantErr: "tool 'orphan' extends Tool/gone, which does not exist"},
+		{name: "self", tool: extendingTool("a", "", "a"), objects: []convertible{extendingTool("a", "", "a")}, wantErr: "cyclic extends chain: Tool/a -> Tool/a"},
+		{
+			name:    "cycle",
+			tool:    extendingTool("a", "", "b"),
+			objects: []convertible{extendingTool("a", "", "b"), extendingTool("b", "", "c"), extendingTool("c", "", "a")},
+			wantErr: "cyclic extends chain: Tool/a -> Tool/b -> Tool/c -> Tool/a",
+		},
+		{
+			name:    "invalid merged tool",
+			tool:    mountsCache,
+			objects: []convertible{mountsCache, newTestTool("b", "")},
+			wantErr: `volume "cache" is not declared in spec.jobTemplate.volumes (extends chain: Tool/a -> Tool/b)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toolClient := newFakeToolClient(t, tt.objects...)
+			resolved, err := resolveTool(toolClient, tt.tool)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("resolveTool() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveTool() error = %v", err)
+			}
+
+			jobTemplate := resolved.Spec.JobTemplate
+			if resolved.Spec.Extends != nil || jobTemplate.Image != "report:2" {
+				t.Errorf("resolved = %+v, want the image of report and no extends", resolved.Spec)
+			}
+			wantEnv := []corev1.EnvVar{{Name: "LEVEL", Value: "debug"}, {Name: "REGION", Value: "eu"}}
+			if len(jobTemplate.Env) != 2 || jobTemplate.Env[0] != wantEnv[0] || jobTemplate.Env[1] != wantEnv[1] {
+				t.Errorf("env = %+v, want %+v", jobTemplate.Env, wantEnv)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package rapt
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"codeberg.org/lig/rapt/api/v1alpha1"
+	"github.com/kballard/go-shellquote"
+)
+
+// ShowToolHelp prints usage, help text, arguments and examples for a tool.
+// Arguments whose names are in reservedFlags clash with flags of `rapt run`
+// and are shown in the --arg form.
+func ShowToolHelp(tool *v1alpha1.Tool, reservedFlags map[string]bool) error {
+	if tool.Spec.Help != "" {
+		fmt.Printf("%s - %s\n\n", tool.Name, tool.Spec.Help)
+	} else {
+		fmt.Printf("%s\n\n", tool.Name)
+	}
+
+	fmt.Println("Usage:")
+	usage := []string{"rapt", "run", tool.Name}
+	for _, arg := range tool.Spec.Arguments {
+		part := argumentUsage(arg, fmt.Sprintf("<%s>", arg.ArgType()), reservedFlags)
+		if !arg.Required {
+			part = "[" + part + "]"
+		}
+		usage = append(usage, part)
+	}
+	fmt.Printf("  %s [flags]\n", strings.Join(usage, " "))
+
+	if len(tool.Spec.Arguments) > 0 {
+		fmt.Println()
+		fmt.Println("Arguments:")
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, arg := range tool.Spec.Arguments {
+			flag := "--" + arg.Name
+			if reservedFlags[arg.Name] {
+				flag = fmt.Sprintf("--arg %s=", arg.Name)
+			}
+			details := []string{string(arg.ArgType())}
+			if arg.Required {
+				details = append(details, "required")
+			}
+			if arg.Default != "" {
+				details = append(details, "default "+arg.Default)
+			}
+			if constraints := describeArgumentConstraints(arg); constraints != "" {
+				details = append(details, constraints)
+			}
+			fmt.Fprintf(w, "  %s\t%s\t(%s)\n", flag, arg.Description, strings.Join(details, "; "))
+		}
+		w.Flush()
+	}
+
+	commandLine, err := describeCommandLine(tool, nil)
+	if err != nil {
+		return err
+	}
+	fmt.Println()
+	fmt.Println("Runs:")
+	fmt.Printf("  %s\n", commandLine)
+
+	fmt.Println()
+	fmt.Println("Examples:")
+	for _, example := range toolExamples(tool, reservedFlags) {
+		fmt.Printf("  %s\n", example)
+	}
+
+	fmt.Println()
+	fmt.Println("Use \"rapt run --help\" for the flags that apply to every run.")
+	return nil
+}
+
+// toolExamples returns an invocation with the required arguments only and,
+// if the tool has optional arguments, one with every argument set
+func toolExamples(tool *v1alpha1.Tool, reservedFlags map[string]bool) []string {
+	required := []string{"rapt", "run", tool.Name}
+	all := []string{"rapt", "run", tool.Name}
+	hasOptional := false
+	for _, arg := range tool.Spec.Arguments {
+		part := argumentUsage(arg, shellquote.Join(exampleValue(arg)), reservedFlags)
+		if arg.Required {
+			required = append(required, part)
+		} else {
+			hasOptional = true
+		}
+		all = append(all, part)
+	}
+
+	examples := []string{strings.Join(required, " ")}
+	if hasOptional {
+		examples = append(examples, strings.Join(all, " "))
+	}
+	return examples
+}
+
+// argumentUsage returns how an argument with the given value is written on the `rapt run` command line
+func argumentUsage(arg v1alpha1.Argument, value string, reservedFlags map[string]bool) string {
+	if reservedFlags[arg.Name] {
+		return fmt.Sprintf("--arg %s=%s", arg.Name, value)
+	}
+	// Boolean flags do not consume the next word, so their value has to be attached
+	if arg.ArgType() == v1alpha1.ArgumentTypeBool {
+		if value == "true" {
+			return "--" + arg.Name
+		}
+		return fmt.Sprintf("--%s=%s", arg.Name, value)
+	}
+	return fmt.Sprintf("--%s %s", arg.Name, value)
+}
+
+// exampleValue returns a plausible value for an argument
+func exampleValue(arg v1alpha1.Argument) string {
+	if arg.Default != "" {
+		return arg.Default
+	}
+	switch arg.ArgType() {
+	case v1alpha1.ArgumentTypeEnum:
+		if len(arg.Values) > 0 {
+			return arg.Values[0]
+		}
+	case v1alpha1.ArgumentTypeInt:
+		if arg.Min != nil {
+			return arg.Min.String()
+		}
+		return "1"
+	case v1alpha1.ArgumentTypeBool:
+		return "true"
+	case v1alpha1.ArgumentTypeDuration:
+		if arg.Min != nil {
+			return arg.Min.String()
+		}
+		return "30s"
+	case v1alpha1.ArgumentTypePath:
+		return "/path/to/" + arg.Name
+	}
+	return arg.Name
+}
@@ -0,0 +1,232 @@
+package rapt
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"codeberg.org/lig/rapt/api/v1alpha1"
+	"codeberg.org/lig/rapt/internal/k8s"
+	"github.com/pmezard/go-difflib/difflib"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	yamlv2 "sigs.k8s.io/yaml"
+)
+
+// ShowHistory lists the recorded revisions of a tool
+func ShowHistory(namespace, toolName string) error {
+	toolClient, tool, err := getVersionedTool(namespace, toolName)
+	if err != nil {
+		return err
+	}
+
+	revisions, err := toolClient.ListRevisions(context.TODO(), tool)
+	if err != nil {
+		return fmt.Errorf("failed to list revisions: %w", err)
+	}
+	if len(revisions) == 0 {
+		fmt.Printf("No revisions recorded for tool '%s'.\n", toolName)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "REVISION\tCREATED\tCHANGE-CAUSE")
+	current := tool.CurrentRevision()
+	for _, revision := range revisions {
+		number := strconv.FormatInt(revision.Revision, 10)
+		if revision.Revision == current {
+			number += " (current)"
+		}
+		changeCause := revision.Annotations[v1alpha1.ChangeCauseAnnotation]
+		if changeCause == "" {
+			changeCause = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", number, revision.CreationTimestamp.Format("2006-01-02 15:04:05"), changeCause)
+	}
+	w.Flush()
+
+	if latest := revisions[len(revisions)-1]; !equality.Semantic.DeepEqual(latest.Spec, tool.Spec) {
+		fmt.Println("\nThe tool was changed outside of rapt since the latest revision; the change is not recorded yet.")
+	}
+	return nil
+}
+
+// DiffRevision shows how the current spec of a tool differs from one of its revisions
+func DiffRevision(namespace, toolName string, revision int64) error {
+	toolClient, tool, err := getVersionedTool(namespace, toolName)
+	if err != nil {
+		return err
+	}
+
+	target, err := findRevision(toolClient, tool, revision)
+	if err != nil {
+		return err
+	}
+
+	from, err := yamlv2.Marshal(target.Spec)
+	if err != nil {
+		return fmt.Errorf("failed to marshal revision %d: %w", revision, err)
+	}
+	to, err := yamlv2.Marshal(tool.Spec)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tool %s: %w", toolName, err)
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(from)),
+		B:        difflib.SplitLines(string(to)),
+		FromFile: fmt.Sprintf("%s revision %d", toolName, revision),
+		ToFile:   fmt.Sprintf("%s current", toolName),
+		Context:  3,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to compute diff: %w", err)
+	}
+	if diff == "" {
+		fmt.Printf("Tool '%s' is identical to revision %d.\n", toolName, revision)
+		return nil
+	}
+	fmt.Print(diff)
+	return nil
+}
+
+// RollbackTool restores the spec of a tool from one of its revisions.
+// The restored spec is recorded as a new revision, so the rollback can be undone as well.
+func RollbackTool(namespace, toolName string, revision int64) error {
+	toolClient, tool, err := getVersionedTool(namespace, toolName)
+	if err != nil {
+		return err
+	}
+	return rollbackTool(toolClient, tool, revision)
+}
+
+// rollbackTool restores the spec of a tool read with loadVersionedTool from one of its revisions
+func rollbackTool(toolClient *k8s.ToolClient, tool *v1alpha1.Tool, revision int64) error {
+	toolName := tool.Name
+	target, err := findRevision(toolClient, tool, revision)
+	if err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(target.Spec, tool.Spec) {
+		fmt.Printf("Tool '%s' already matches revision %d.\n", toolName, revision)
+		return nil
+	}
+
+	// Keep changes made outside of rapt, so that they can be restored later
+	tool, err = saveToolRevision(toolClient, tool, "changed outside of rapt")
+	if err != nil {
+		return err
+	}
+
+	tool.Spec = *target.Spec.DeepCopy()
+	updated, err := toolClient.Update(context.TODO(), tool)
+	if err != nil {
+		return fmt.Errorf("failed to update tool %s: %w", toolName, err)
+	}
+	updated, err = saveToolRevision(toolClient, updated, fmt.Sprintf("rollback to revision %d", revision))
+	if err != nil {
+		return err
+	}
+	recordArgumentCount(toolClient, v1alpha1.ToolKind, updated.Namespace, toolName)
+
+	fmt.Printf("Tool '%s' rolled back to revision %d (recorded as revision %d)\n", toolName, revision, updated.CurrentRevision())
+	return nil
+}
+
+// saveToolRevision records the spec of a tool as a new ToolRevision, unless it is the
+// same as the latest revision, and points the revision annotation of the tool to it.
+// It returns the tool as updated in the cluster.
+func saveToolRevision(toolClient *k8s.ToolClient, tool *v1alpha1.Tool, changeCause string) (*v1alpha1.Tool, error) {
+	revisions, err := toolClient.ListRevisions(context.TODO(), tool)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list revisions of tool %s: %w", tool.Name, err)
+	}
+
+	var number int64 = 1
+	if len(revisions) > 0 {
+		latest := revisions[len(revisions)-1]
+		number = latest.Revision + 1
+		if equality.Semantic.DeepEqual(latest.Spec, tool.Spec) {
+			number = latest.Revision
+		}
+	}
+	if number == tool.CurrentRevision() {
+		return tool, nil
+	}
+
+	if len(revisions) == 0 || number > revisions[len(revisions)-1].Revision {
+		if err := toolClient.CreateRevision(context.TODO(), v1alpha1.NewToolRevision(tool, number, changeCause)); err != nil {
+			return nil, fmt.Errorf("failed to record revision %d of tool %s: %w", number, tool.Name, err)
+		}
+	}
+
+	tool = tool.DeepCopy()
+	if tool.Annotations == nil {
+		tool.Annotations = make(map[string]string)
+	}
+	tool.Annotations[v1alpha1.RevisionAnnotation] = strconv.FormatInt(number, 10)
+	updated, err := toolClient.Update(context.TODO(), tool)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set revision of tool %s: %w", tool.Name, err)
+	}
+	return updated, nil
+}
+
+// saveObjectRevision records a revision of a Tool read as an unstructured object.
+// ClusterTools have no revision history, so nothing is recorded for them.
+func saveObjectRevision(toolClient *k8s.ToolClient, object *unstructured.Unstructured, changeCause string) error {
+	if object.GetKind() != v1alpha1.ToolKind {
+		return nil
+	}
+	tool, err := v1alpha1.ToolFromUnstructured(object)
+	if err != nil {
+		return err
+	}
+	_, err = saveToolRevision(toolClient, tool, changeCause)
+	return err
+}
+
+// getVersionedTool returns a tool client and the namespaced tool whose history is requested.
+// ClusterTools have no revision history.
+func getVersionedTool(namespace, toolName string) (*k8s.ToolClient, *v1alpha1.Tool, error) {
+	toolClient, err := k8s.InitToolClient(namespace)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to initialize tool client: %w", err)
+	}
+
+	tool, err := loadVersionedTool(toolClient, namespace, toolName)
+	if err != nil {
+		return nil, nil, err
+	}
+	return toolClient, tool, nil
+}
+
+// loadVersionedTool reads a namespaced tool without validating it, like rapt edit does:
+// a tool made invalid by a change outside of rapt is the one that most needs a rollback.
+func loadVersionedTool(toolClient *k8s.ToolClient, namespace, toolName string) (*v1alpha1.Tool, error) {
+	object, err := toolClient.GetObject(context.TODO(), v1alpha1.ToolKind, namespace, toolName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("tool '%s' not found in namespace '%s' (revision history is only kept for namespaced tools)", toolName, namespace)
+		}
+		return nil, fmt.Errorf("failed to get tool definition: %w", err)
+	}
+	return v1alpha1.DecodeTool(object)
+}
+
+// findRevision returns the given revision of a tool
+func findRevision(toolClient *k8s.ToolClient, tool *v1alpha1.Tool, revision int64) (*v1alpha1.ToolRevision, error) {
+	revisions, err := toolClient.ListRevisions(context.TODO(), tool)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list revisions: %w", err)
+	}
+	for i := range revisions {
+		if revisions[i].Revision == revision {
+			return &revisions[i], nil
+		}
+	}
+	return nil, fmt.Errorf("revision %d of tool '%s' not found, see 'rapt history %s'", revision, tool.Name, tool.Name)
+}
@@ -0,0 +1,109 @@
+package rapt
+
+import (
+	"context"
+	"testing"
+
+	"codeberg.org/lig/rapt/api/v1alpha1"
+	"codeberg.org/lig/rapt/internal/k8s"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynfake "k8s.io/client-go/dynamic/fake"
+)
+
+// convertible is implemented by the Tool, ClusterTool and ToolRevision types
+type convertible interface {
+	ToUnstructured() (*unstructured.Unstructured, error)
+}
+
+// newFakeToolClient returns a tool client backed by a fake dynamic client holding the given objects
+func newFakeToolClient(t *testing.T, objects ...convertible) *k8s.ToolClient {
+	t.Helper()
+	var objs []runtime.Object
+	for _, object := range objects {
+		u, err := object.ToUnstructured()
+		if err != nil {
+			t.Fatalf("ToUnstructured() error = %v", err)
+		}
+		objs = append(objs, u)
+	}
+	dynClient := dynfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		v1alpha1.ToolResource:         "ToolList",
+		v1alpha1.ClusterToolResource:  "ClusterToolList",
+		v1alpha1.ToolRevisionResource: "ToolRevisionList",
+	}, objs...)
+	return k8s.NewToolClient(dynClient)
+}
+
+// newTestTool returns a valid Tool with the given name and UID in the default namespace
+func newTestTool(name, uid string) *v1alpha1.Tool {
+	return &v1alpha1.Tool{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.GroupVersion.String(), Kind: v1alpha1.ToolKind},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID(uid)},
+		Spec:       v1alpha1.ToolSpec{JobTemplate: v1alpha1.JobTemplate{Image: "alpine:3.20"}},
+	}
+}
+
+func TestSaveToolRevisionAfterRecreate(t *testing.T) {
+	deleted := newTestTool("report", "11111111-aaaa")
+	recreated := newTestTool("report", "22222222-bbbb")
+	recreated.Spec.JobTemplate.Image = "alpine:3.21"
+
+	// Revisions of the deleted tool that the garbage collector has not removed yet
+	leftover := v1alpha1.NewToolRevision(deleted, 1, "rapt add")
+	legacy := v1alpha1.NewToolRevision(deleted, 2, "rapt update")
+	legacy.Name = "report-1"
+	toolClient := newFakeToolClient(t, recreated, leftover, legacy)
+
+	saved, err := saveToolRevision(toolClient, recreated, "rapt add")
+	if err != nil {
+		t.Fatalf("saveToolRevision() error = %v", err)
+	}
+	if got := saved.CurrentRevision(); got != 1 {
+		t.Errorf("current revision = %d, want 1", got)
+	}
+
+	revisions, err := toolClient.ListRevisions(context.TODO(), recreated)
+	if err != nil {
+		t.Fatalf("ListRevisions() error = %v", err)
+	}
+	if len(revisions) != 1 || revisions[0].Spec.JobTemplate.Image != "alpine:3.21" {
+		t.Errorf("revisions = %+v, want revision 1 of the recreated tool only", revisions)
+	}
+}
+
+func TestRollbackInvalidTool(t *testing.T) {
+	valid := newTestTool("report", "11111111-aaaa")
+	revision := v1alpha1.NewToolRevision(valid, 1, "rapt add")
+	// Changed outside of rapt into a tool that fails validation
+	broken := valid.DeepCopy()
+	broken.Annotations = map[string]string{v1alpha1.RevisionAnnotation: "1"}
+	broken.Spec.JobTemplate.Image = ""
+	toolClient := newFakeToolClient(t, broken, revision)
+
+	tool, err := loadVersionedTool(toolClient, "default", "report")
+	if err != nil {
+		t.Fatalf("loadVersionedTool() error = %v", err)
+	}
+	if err := rollbackTool(toolClient, tool, 1); err != nil {
+		t.Fatalf("rollbackTool() error = %v", err)
+	}
+
+	restored, err := toolClient.Get(context.TODO(), "default", "report")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if restored.Spec.JobTemplate.Image != "alpine:3.20" || restored.CurrentRevision() != 3 {
+		t.Errorf("restored = image %q, revision %d, want alpine:3.20 and revision 3", restored.Spec.JobTemplate.Image, restored.CurrentRevision())
+	}
+	revisions, err := toolClient.ListRevisions(context.TODO(), restored)
+	if err != nil {
+		t.Fatalf("ListRevisions() error = %v", err)
+	}
+	if len(revisions) != 3 || revisions[1].Spec.JobTemplate.Image != "" {
+		t.Errorf("revisions = %+v, want the invalid spec recorded as revision 2", revisions)
+	}
+}
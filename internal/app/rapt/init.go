@@ -5,19 +5,30 @@ import (
 	"fmt"
 
 	"codeberg.org/lig/rapt/internal/k8s"
+	apiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	clientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func InitCmd(namespace string, dryRun bool) error {
-	crd, err := k8s.LoadToolCRD()
+	crds, err := k8s.LoadCRDs()
 	if err != nil {
 		return fmt.Errorf("failed to unmarshal tool.yaml: %w", err)
 	}
 
 	// If dry-run mode, print YAML and exit
 	if dryRun {
-		return k8s.PrintCRDYAML(crd)
+		for i, crd := range crds {
+			if i > 0 {
+				fmt.Println("---")
+			}
+			if err := k8s.PrintCRDYAML(crd); err != nil {
+				return err
+			}
+		}
+		return nil
 	}
 
 	k8sClient, err := k8s.InitClient(namespace)
@@ -25,15 +36,48 @@ func InitCmd(namespace string, dryRun bool) error {
 		return err
 	}
 
-	_, err = k8sClient.ApiextensionsV1().CustomResourceDefinitions().Create(context.TODO(), crd, metav1.CreateOptions{})
+	for _, crd := range crds {
+		if err := installCRD(k8sClient, crd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// installCRD creates the CRD, or upgrades it if it is already installed
+func installCRD(k8sClient *clientset.Clientset, crd *apiv1.CustomResourceDefinition) error {
+	crdClient := k8sClient.ApiextensionsV1().CustomResourceDefinitions()
+	_, err := crdClient.Create(context.TODO(), crd, metav1.CreateOptions{})
 	if err != nil {
 		if apierrors.IsAlreadyExists(err) {
-			fmt.Println("CRD already exists. Skipping creation.")
-			return nil
+			return upgradeCRD(k8sClient, crd)
 		}
-		return fmt.Errorf("failed to create CRD: %w", err)
+		return fmt.Errorf("failed to create CRD %s: %w", crd.GetName(), err)
+	}
+
+	fmt.Printf("CRD %s created successfully.\n", crd.GetName())
+	return nil
+}
+
+// upgradeCRD replaces the spec of an already installed CRD with the embedded one
+func upgradeCRD(k8sClient *clientset.Clientset, crd *apiv1.CustomResourceDefinition) error {
+	crdClient := k8sClient.ApiextensionsV1().CustomResourceDefinitions()
+	existing, err := crdClient.Get(context.TODO(), crd.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get existing CRD %s: %w", crd.GetName(), err)
+	}
+
+	if equality.Semantic.DeepDerivative(crd.Spec, existing.Spec) {
+		fmt.Printf("CRD %s is up to date.\n", crd.GetName())
+		return nil
+	}
+
+	existing.Spec = crd.Spec
+	_, err = crdClient.Update(context.TODO(), existing, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to upgrade CRD %s: %w", crd.GetName(), err)
 	}
 
-	fmt.Println("CRD created successfully.")
+	fmt.Printf("CRD %s upgraded successfully.\n", crd.GetName())
 	return nil
 }
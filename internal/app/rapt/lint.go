@@ -0,0 +1,393 @@
+package rapt
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"codeberg.org/lig/rapt/api/v1alpha1"
+	"codeberg.org/lig/rapt/internal/k8s"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Output formats of Validate and Lint
+const (
+	FormatText  = "text"
+	FormatJSON  = "json"
+	FormatSARIF = "sarif"
+)
+
+// Levels of findings
+const (
+	levelError   = "error"
+	levelWarning = "warning"
+)
+
+// Rules that are checked without a check function
+const (
+	ruleSchema        = "schema"
+	ruleDuplicateTool = "duplicate-tool"
+	ruleInvalidTool   = "invalid-tool"
+)
+
+// secretNamePattern matches environment variable names that usually hold credentials
+var secretNamePattern = regexp.MustCompile(`(?i)(^|_)(PASSWORD|PASSWD|SECRET|TOKEN|API_?KEY|PRIVATE_?KEY|ACCESS_?KEY|CREDENTIALS?)$`)
+
+// lintRule is a check that manifests are subjected to
+type lintRule struct {
+	id          string
+	description string
+	level       string
+	// bestPractice rules are only checked by Lint; the others find tools the API server would reject
+	bestPractice bool
+	// check returns a message for every violation of the rule by a tool
+	check func(tool *v1alpha1.Tool) []string
+}
+
+// lintRules are all rules, in the order their findings are reported for a manifest
+var lintRules = []lintRule{
+	{id: ruleSchema, level: levelError, description: "Manifests are Tools or ClusterTools that match the CRD schema"},
+	{id: ruleDuplicateTool, level: levelError, description: "Every tool is defined only once"},
+	{id: "duplicate-env-name", level: levelError, description: "Environment variable names are unique within a container", check: checkDuplicateEnv},
+	{id: "required-argument-default", level: levelError, description: "Required arguments have no default value", check: checkRequiredDefaults},
+	{id: ruleInvalidTool, level: levelError, description: "Tools pass the validation of rapt and the API server"},
+	{id: "image-latest-tag", level: levelWarning, bestPractice: true, description: "Images use a specific tag instead of latest", check: checkLatestTags},
+	{id: "plaintext-secret", level: levelWarning, bestPractice: true, description: "Credentials are read from Secrets instead of plain environment variable values", check: checkPlaintextSecrets},
+	{id: "verbatim-placeholder", level: levelWarning, bestPractice: true, description: "Placeholders meant for arguments only refer to declared arguments", check: checkVerbatimPlaceholders},
+}
+
+// finding is a violation of a rule by a manifest
+type finding struct {
+	File     string `json:"file"`
+	Document int    `json:"document"`
+	Kind     string `json:"kind,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Rule     string `json:"rule"`
+	Level    string `json:"level"`
+	Message  string `json:"message"`
+}
+
+// Validate checks manifests against the CRD schema and the tool validation without a cluster.
+// It reports the problems in the given format and fails if there are any.
+func Validate(paths []string, format string) error {
+	return checkFiles(os.Stdout, paths, format, false)
+}
+
+// Lint checks manifests like Validate and also against the best practices for tools
+func Lint(paths []string, format string) error {
+	return checkFiles(os.Stdout, paths, format, true)
+}
+
+// checkFiles checks the manifests of the given paths with the rules that apply and writes the report to w
+func checkFiles(w io.Writer, paths []string, format string, bestPractices bool) error {
+	if !slices.Contains([]string{FormatText, FormatJSON, FormatSARIF}, format) {
+		return fmt.Errorf("invalid --output value %q, must be one of %s, %s or %s", format, FormatText, FormatJSON, FormatSARIF)
+	}
+
+	manifests, err := readManifests(paths)
+	if err != nil {
+		return err
+	}
+	if len(manifests) == 0 {
+		return fmt.Errorf("no tools found in %s", strings.Join(paths, ", "))
+	}
+	validators, err := loadSchemaValidators()
+	if err != nil {
+		return err
+	}
+
+	var findings []finding
+	seen := make(map[string]string)
+	for _, m := range manifests {
+		findings = append(findings, lintManifest(m, validators, bestPractices)...)
+
+		key := describeManifest(m) + " " + m.object.GetNamespace()
+		if first, exists := seen[key]; exists && m.object.GetName() != "" {
+			findings = append(findings, newFinding(m, ruleDuplicateTool, fmt.Sprintf("%s is already defined in %s", describeManifest(m), first)))
+		}
+		seen[key] = m.source
+	}
+
+	var rules []lintRule
+	for _, rule := range lintRules {
+		if bestPractices || !rule.bestPractice {
+			rules = append(rules, rule)
+		}
+	}
+	if err := printFindings(w, format, findings, rules, len(manifests)); err != nil {
+		return err
+	}
+
+	if len(findings) > 0 {
+		return fmt.Errorf("found %s in %s", countFindings(findings), plural(len(manifests), "tool"))
+	}
+	return nil
+}
+
+// lintManifest checks a single manifest. The tool rules only run on manifests that match the schema,
+// and the complete tool validation only when none of the rules for invalid tools found anything.
+func lintManifest(m manifest, validators map[string]*k8s.SchemaValidator, bestPractices bool) []finding {
+	object := m.object
+	if object.GetAPIVersion() != v1alpha1.GroupVersion.String() {
+		return []finding{newFinding(m, ruleSchema, fmt.Sprintf("unsupported apiVersion %q, expected %s", object.GetAPIVersion(), v1alpha1.GroupVersion))}
+	}
+	validator, supported := validators[object.GetKind()]
+	if !supported {
+		return []finding{newFinding(m, ruleSchema, fmt.Sprintf("unsupported kind %q, expected %s or %s", object.GetKind(), v1alpha1.ToolKind, v1alpha1.ClusterToolKind))}
+	}
+
+	var findings []finding
+	if object.GetName() == "" {
+		findings = append(findings, newFinding(m, ruleSchema, "metadata.name is required"))
+	}
+	if object.GetKind() == v1alpha1.ClusterToolKind && object.GetNamespace() != "" {
+		findings = append(findings, newFinding(m, ruleSchema, "a ClusterTool is cluster-scoped and cannot have a namespace"))
+	}
+	for _, err := range validator.Validate(object.Object) {
+		findings = append(findings, newFinding(m, ruleSchema, err.Error()))
+	}
+	if len(findings) > 0 {
+		return findings
+	}
+
+	defaulted := object.DeepCopy()
+	validator.Default(defaulted.Object)
+	var tool v1alpha1.Tool
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(defaulted.Object, &tool); err != nil {
+		return []finding{newFinding(m, ruleSchema, err.Error())}
+	}
+
+	invalid := false
+	for _, rule := range lintRules {
+		if rule.check == nil || (rule.bestPractice && !bestPractices) {
+			continue
+		}
+		for _, message := range rule.check(&tool) {
+			findings = append(findings, newFinding(m, rule.id, message))
+			invalid = invalid || !rule.bestPractice
+		}
+	}
+	if !invalid {
+		if err := tool.Validate(); err != nil {
+			findings = append(findings, newFinding(m, ruleInvalidTool, err.Error()))
+		}
+	}
+	return findings
+}
+
+// newFinding returns a finding of a rule for a manifest
+func newFinding(m manifest, ruleID, message string) finding {
+	level := levelError
+	for _, rule := range lintRules {
+		if rule.id == ruleID {
+			level = rule.level
+		}
+	}
+	return finding{
+		File:     m.file,
+		Document: m.document,
+		Kind:     m.object.GetKind(),
+		Name:     m.object.GetName(),
+		Rule:     ruleID,
+		Level:    level,
+		Message:  message,
+	}
+}
+
+// containerSpec is the image and environment of one container of a tool
+type containerSpec struct {
+	path  string
+	image string
+	env   []corev1.EnvVar
+}
+
+// toolContainers returns the tool container followed by the init containers and sidecars
+func toolContainers(tool *v1alpha1.Tool) []containerSpec {
+	jobTemplate := &tool.Spec.JobTemplate
+	containers := []containerSpec{{"spec.jobTemplate", jobTemplate.Image, jobTemplate.Env}}
+	for i, container := range jobTemplate.InitContainers {
+		containers = append(containers, containerSpec{fmt.Sprintf("spec.jobTemplate.initContainers[%d]", i), container.Image, container.Env})
+	}
+	for i, container := range jobTemplate.Sidecars {
+		containers = append(containers, containerSpec{fmt.Sprintf("spec.jobTemplate.sidecars[%d]", i), container.Image, container.Env})
+	}
+	return containers
+}
+
+// checkDuplicateEnv reports environment variables that are set twice in the same container
+func checkDuplicateEnv(tool *v1alpha1.Tool) []string {
+	var messages []string
+	for _, container := range toolContainers(tool) {
+		seen := make(map[string]bool, len(container.env))
+		for i, env := range container.env {
+			if seen[env.Name] {
+				messages = append(messages, fmt.Sprintf("%s.env[%d]: environment variable %s is already set", container.path, i, env.Name))
+			}
+			seen[env.Name] = true
+		}
+	}
+	return messages
+}
+
+// checkRequiredDefaults reports required arguments with a default value, which is never used
+func checkRequiredDefaults(tool *v1alpha1.Tool) []string {
+	var messages []string
+	for i, arg := range tool.Spec.Arguments {
+		if arg.Required && arg.Default != "" {
+			messages = append(messages, fmt.Sprintf("spec.arguments[%d]: argument %s is required, so its default %q is never used", i, arg.Name, arg.Default))
+		}
+	}
+	return messages
+}
+
+// checkLatestTags reports images without a tag or with the latest tag, which change without notice
+func checkLatestTags(tool *v1alpha1.Tool) []string {
+	var messages []string
+	for _, container := range toolContainers(tool) {
+		if container.image != "" && usesLatestTag(container.image) {
+			messages = append(messages, fmt.Sprintf("%s.image: %s uses the latest tag, pin a specific tag or digest", container.path, container.image))
+		}
+	}
+	return messages
+}
+
+// usesLatestTag reports whether an image reference resolves to the latest tag
+func usesLatestTag(image string) bool {
+	if strings.Contains(image, "@") {
+		return false
+	}
+	name := image[strings.LastIndex(image, "/")+1:]
+	i := strings.LastIndex(name, ":")
+	return i < 0 || name[i+1:] == "latest"
+}
+
+// checkPlaintextSecrets reports environment variables named like credentials that are set to plain values
+func checkPlaintextSecrets(tool *v1alpha1.Tool) []string {
+	var messages []string
+	for _, container := range toolContainers(tool) {
+		for i, env := range container.env {
+			if env.Value != "" && secretNamePattern.MatchString(env.Name) {
+				messages = append(messages, fmt.Sprintf("%s.env[%d]: %s looks like a credential but is set to a plain value, read it from a Secret with valueFrom.secretKeyRef", container.path, i, env.Name))
+			}
+		}
+	}
+	return messages
+}
+
+// checkVerbatimPlaceholders reports command and args elements that are passed verbatim although
+// their placeholders look meant for arguments: they also refer to a declared argument, or to a
+// name that is close to one. Placeholders for other tools, like go-template={{.metadata.name}}, are fine.
+func checkVerbatimPlaceholders(tool *v1alpha1.Tool) []string {
+	// Arguments of the base tool are only known once the tool is merged with it
+	if tool.Spec.Extends != nil {
+		return nil
+	}
+	declared := make([]string, len(tool.Spec.Arguments))
+	for i, arg := range tool.Spec.Arguments {
+		declared[i] = arg.Name
+	}
+
+	var messages []string
+	jobTemplate := &tool.Spec.JobTemplate
+	for _, field := range []struct {
+		path  string
+		parts []string
+	}{
+		{"spec.jobTemplate.command", jobTemplate.Command},
+		{"spec.jobTemplate.args", jobTemplate.Args},
+	} {
+		for i, part := range field.parts {
+			if tool.Spec.ParsePlaceholders(part) != nil {
+				continue
+			}
+			names := v1alpha1.PlaceholderNames(part)
+			refersToArgument := slices.ContainsFunc(names, func(name string) bool { return slices.Contains(declared, name) })
+			for _, name := range names {
+				if slices.Contains(declared, name) {
+					continue
+				}
+				if suggestion := suggestArgument(name, declared); suggestion != "" {
+					messages = append(messages, fmt.Sprintf("%s[%d]: .%s is not a declared argument (did you mean %s?), so the element is passed verbatim", field.path, i, name, suggestion))
+					break
+				}
+				if refersToArgument {
+					messages = append(messages, fmt.Sprintf("%s[%d]: .%s is not a declared argument, so the element is passed verbatim", field.path, i, name))
+					break
+				}
+			}
+		}
+	}
+	return messages
+}
+
+// countFindings describes the number of errors and warnings, e.g. "2 errors and 1 warning"
+func countFindings(findings []finding) string {
+	errors, warnings := 0, 0
+	for _, f := range findings {
+		if f.Level == levelError {
+			errors++
+		} else {
+			warnings++
+		}
+	}
+	return fmt.Sprintf("%s and %s", plural(errors, "error"), plural(warnings, "warning"))
+}
+
+// plural formats a count with a singular or plural noun
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// printFindings writes the report of the findings in the given format
+func printFindings(w io.Writer, format string, findings []finding, rules []lintRule, tools int) error {
+	switch format {
+	case FormatJSON:
+		return printFindingsJSON(w, findings, tools)
+	case FormatSARIF:
+		return printFindingsSARIF(w, findings, rules)
+	default:
+		printFindingsText(w, findings, tools)
+		return nil
+	}
+}
+
+// printFindingsText writes one line per finding, or a confirmation if there are none
+func printFindingsText(w io.Writer, findings []finding, tools int) {
+	if len(findings) == 0 {
+		fmt.Fprintf(w, "%s checked, no problems found\n", plural(tools, "tool"))
+		return
+	}
+	for _, f := range findings {
+		object := ""
+		if f.Name != "" {
+			object = fmt.Sprintf(" %s/%s", f.Kind, f.Name)
+		}
+		fmt.Fprintf(w, "%s (document %d)%s: %s: %s [%s]\n", f.File, f.Document, object, f.Level, f.Message, f.Rule)
+	}
+}
+
+// printFindingsJSON writes the findings as a JSON report
+func printFindingsJSON(w io.Writer, findings []finding, tools int) error {
+	if findings == nil {
+		findings = []finding{}
+	}
+	report := struct {
+		Tools    int       `json:"tools"`
+		Findings []finding `json:"findings"`
+	}{tools, findings}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("failed to write the report: %w", err)
+	}
+	return nil
+}
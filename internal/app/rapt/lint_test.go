@@ -0,0 +1,211 @@
+package rapt
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"codeberg.org/lig/rapt/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var update = flag.Bool("update", false, "update the golden files of the report tests")
+
+func TestUsesLatestTag(t *testing.T) {
+	tests := []struct {
+		image string
+		want  bool
+	}{
+		{"alpine", true},
+		{"alpine:latest", true},
+		{"docker.io/library/alpine:latest", true},
+		{"registry.example.com:5000/team/tool", true},
+		{"alpine:3.20", false},
+		{"registry.example.com:5000/team/tool:1.2", false},
+		{"alpine@sha256:0123456789abcdef", false},
+		{"alpine:latest@sha256:0123456789abcdef", false},
+		{"alpine:latest-slim", false},
+	}
+	for _, tt := range tests {
+		if got := usesLatestTag(tt.image); got != tt.want {
+			t.Errorf("usesLatestTag(%q) = %v, want %v", tt.image, got, tt.want)
+		}
+	}
+}
+
+func TestSecretNamePattern(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"PASSWORD", true},
+		{"DB_PASSWORD", true},
+		{"db_password", true},
+		{"PGPASSWD", false},
+		{"PG_PASSWD", true},
+		{"API_TOKEN", true},
+		{"GITHUB_APIKEY", true},
+		{"AWS_SECRET_ACCESS_KEY", true},
+		{"SSH_PRIVATE_KEY", true},
+		{"GOOGLE_CREDENTIALS", true},
+		{"PASSWORD_FILE", false},
+		{"TOKEN_URL", false},
+		{"SECRETARY", false},
+		{"LOG_LEVEL", false},
+	}
+	for _, tt := range tests {
+		if got := secretNamePattern.MatchString(tt.name); got != tt.want {
+			t.Errorf("secretNamePattern.MatchString(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLintRuleChecks(t *testing.T) {
+	tests := []struct {
+		name  string
+		check func(tool *v1alpha1.Tool) []string
+		spec  v1alpha1.ToolSpec
+		want  []string
+	}{
+		{
+			name:  "duplicate env in the tool container",
+			check: checkDuplicateEnv,
+			spec:  v1alpha1.ToolSpec{JobTemplate: v1alpha1.JobTemplate{Env: []corev1.EnvVar{{Name: "A"}, {Name: "B"}, {Name: "A"}}}},
+			want:  []string{"spec.jobTemplate.env[2]: environment variable A is already set"},
+		},
+		{
+			name:  "same env in different containers",
+			check: checkDuplicateEnv,
+			spec: v1alpha1.ToolSpec{JobTemplate: v1alpha1.JobTemplate{
+				Env:      []corev1.EnvVar{{Name: "A"}},
+				Sidecars: []corev1.Container{{Name: "proxy", Env: []corev1.EnvVar{{Name: "A"}, {Name: "A"}}}},
+			}},
+			want: []string{"spec.jobTemplate.sidecars[0].env[1]: environment variable A is already set"},
+		},
+		{
+			name:  "required argument with default",
+			check: checkRequiredDefaults,
+			spec: v1alpha1.ToolSpec{Arguments: []v1alpha1.Argument{
+				{Name: "a", Required: true},
+				{Name: "b", Default: "x"},
+				{Name: "c", Required: true, Default: "y"},
+			}},
+			want: []string{`spec.arguments[2]: argument c is required, so its default "y" is never used`},
+		},
+		{
+			name:  "latest tags of all containers",
+			check: checkLatestTags,
+			spec: v1alpha1.ToolSpec{JobTemplate: v1alpha1.JobTemplate{
+				Image:          "alpine:3.20",
+				InitContainers: []corev1.Container{{Name: "setup", Image: "busybox"}},
+				Sidecars:       []corev1.Container{{Name: "proxy", Image: "proxy:latest"}},
+			}},
+			want: []string{
+				"spec.jobTemplate.initContainers[0].image: busybox uses the latest tag, pin a specific tag or digest",
+				"spec.jobTemplate.sidecars[0].image: proxy:latest uses the latest tag, pin a specific tag or digest",
+			},
+		},
+		{
+			name:  "image left to the base tool",
+			check: checkLatestTags,
+			spec:  v1alpha1.ToolSpec{Extends: &v1alpha1.ToolReference{Name: "base"}},
+		},
+		{
+			name:  "plaintext secrets",
+			check: checkPlaintextSecrets,
+			spec: v1alpha1.ToolSpec{JobTemplate: v1alpha1.JobTemplate{
+				Env: []corev1.EnvVar{
+					{Name: "DB_PASSWORD", Value: "secret"},
+					{Name: "API_TOKEN", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{Key: "token"}}},
+					{Name: "EMPTY_SECRET"},
+					{Name: "LOG_LEVEL", Value: "debug"},
+				},
+			}},
+			want: []string{"spec.jobTemplate.env[0]: DB_PASSWORD looks like a credential but is set to a plain value, read it from a Secret with valueFrom.secretKeyRef"},
+		},
+		{
+			name:  "verbatim placeholders",
+			check: checkVerbatimPlaceholders,
+			spec: v1alpha1.ToolSpec{
+				Arguments: []v1alpha1.Argument{{Name: "input"}, {Name: "selector"}},
+				JobTemplate: v1alpha1.JobTemplate{
+					Command: []string{"kubectl", "get", "pods", "-o", "go-template={{.metadata.name}}", "{{ .inptu }}"},
+					Args:    []string{"-l", "{{ .selector }}", "{{ .input }}-{{ .suffix }}"},
+				},
+			},
+			want: []string{
+				"spec.jobTemplate.command[5]: .inptu is not a declared argument (did you mean input?), so the element is passed verbatim",
+				"spec.jobTemplate.args[2]: .suffix is not a declared argument, so the element is passed verbatim",
+			},
+		},
+		{
+			name:  "placeholders for arguments of the base tool",
+			check: checkVerbatimPlaceholders,
+			spec:  v1alpha1.ToolSpec{Extends: &v1alpha1.ToolReference{Name: "base"}, JobTemplate: v1alpha1.JobTemplate{Args: []string{"{{ .input }}"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.check(&v1alpha1.Tool{Spec: tt.spec})
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("messages = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckFilesReports(t *testing.T) {
+	tests := []struct {
+		name          string
+		format        string
+		bestPractices bool
+		golden        string
+		wantErr       string
+	}{
+		{name: "validate text", format: FormatText, golden: "validate.txt", wantErr: "found 5 errors and 0 warnings in 6 tools"},
+		{name: "lint text", format: FormatText, bestPractices: true, golden: "lint.txt", wantErr: "found 5 errors and 3 warnings in 6 tools"},
+		{name: "lint json", format: FormatJSON, bestPractices: true, golden: "lint.json", wantErr: "found 5 errors and 3 warnings in 6 tools"},
+		{name: "lint sarif", format: FormatSARIF, bestPractices: true, golden: "lint.sarif", wantErr: "found 5 errors and 3 warnings in 6 tools"},
+		{name: "invalid format", format: "xml", wantErr: `invalid --output value "xml"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := checkFiles(&out, []string{"testdata/lint/tools.yaml"}, tt.format, tt.bestPractices)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("checkFiles() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+			if tt.golden == "" {
+				return
+			}
+
+			golden := filepath.Join("testdata", "lint", tt.golden)
+			if *update {
+				if err := os.WriteFile(golden, out.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != string(want) {
+				t.Errorf("report differs from %s (run go test -update to refresh it):\n%s", golden, out.String())
+			}
+		})
+	}
+}
+
+func TestCheckFilesClean(t *testing.T) {
+	var out bytes.Buffer
+	if err := checkFiles(&out, []string{"../../../examples"}, FormatText, true); err != nil {
+		t.Fatalf("checkFiles() error = %v\n%s", err, out.String())
+	}
+	if want := "3 tools checked, no problems found\n"; out.String() != want {
+		t.Errorf("report = %q, want %q", out.String(), want)
+	}
+}
@@ -5,60 +5,125 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 	"text/tabwriter"
 	"time"
 
+	"codeberg.org/lig/rapt/api/v1alpha1"
 	"codeberg.org/lig/rapt/internal/k8s"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/client-go/dynamic"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/duration"
 	yamlv2 "sigs.k8s.io/yaml"
 )
 
 // ToolInfo represents information about a tool for display
 type ToolInfo struct {
-	Name        string            `json:"name"`
-	Namespace   string            `json:"namespace"`
-	Image       string            `json:"image"`
-	Command     []string          `json:"command,omitempty"`
-	Arguments   []ToolArgument    `json:"arguments,omitempty"`
-	Environment []ToolEnvironment `json:"environment,omitempty"`
-	Help        string            `json:"help,omitempty"`
-	Created     time.Time         `json:"created"`
+	Name            string            `json:"name"`
+	Namespace       string            `json:"namespace,omitempty"`
+	Source          string            `json:"source"`
+	Extends         string            `json:"extends,omitempty"`
+	Image           string            `json:"image"`
+	Command         []string          `json:"command,omitempty"`
+	Args            []string          `json:"args,omitempty"`
+	CommandLine     string            `json:"commandLine,omitempty"`
+	Arguments       []ToolArgument    `json:"arguments,omitempty"`
+	Environment     []ToolEnvironment `json:"environment,omitempty"`
+	EnvFrom         []ToolEnvSource   `json:"envFrom,omitempty"`
+	Resources       *ToolResources    `json:"resources,omitempty"`
+	Volumes         []ToolVolume      `json:"volumes,omitempty"`
+	ServiceAccount  string            `json:"serviceAccount,omitempty"`
+	SecurityProfile string            `json:"securityProfile,omitempty"`
+	Help            string            `json:"help,omitempty"`
+	Created         time.Time         `json:"created"`
+	Status          *ToolRunStatus    `json:"status,omitempty"`
+}
+
+// ToolRunStatus summarizes the finished runs of a tool
+type ToolRunStatus struct {
+	LastRunTime   *time.Time `json:"lastRunTime,omitempty"`
+	LastRunResult string     `json:"lastRunResult,omitempty"`
+	LastJobName   string     `json:"lastJobName,omitempty"`
+	SucceededRuns int64      `json:"succeededRuns"`
+	FailedRuns    int64      `json:"failedRuns"`
 }
 
 // ToolArgument represents a tool argument
 type ToolArgument struct {
 	Name        string `json:"name"`
+	Type        string `json:"type"`
 	Description string `json:"description,omitempty"`
 	Required    bool   `json:"required"`
 	Default     string `json:"default,omitempty"`
+	Constraints string `json:"constraints,omitempty"`
 }
 
-// ToolEnvironment represents a tool environment variable
+// ToolEnvironment represents a tool environment variable.
+// Variables read from Secrets, ConfigMaps or pod fields have no Value but a Source.
 type ToolEnvironment struct {
-	Name  string `json:"name"`
-	Value string `json:"value"`
+	Name   string `json:"name"`
+	Value  string `json:"value,omitempty"`
+	Source string `json:"source,omitempty"`
+}
+
+// ToolEnvSource represents a Secret or ConfigMap imported as environment variables
+type ToolEnvSource struct {
+	Kind     string `json:"kind"`
+	Name     string `json:"name"`
+	Prefix   string `json:"prefix,omitempty"`
+	Optional bool   `json:"optional,omitempty"`
+}
+
+// ToolResources represents the compute resources of a tool container
+type ToolResources struct {
+	Requests map[string]string `json:"requests,omitempty"`
+	Limits   map[string]string `json:"limits,omitempty"`
+}
+
+// ToolVolume represents a volume declared by a tool and where it is mounted
+type ToolVolume struct {
+	Name      string `json:"name"`
+	Source    string `json:"source"`
+	MountPath string `json:"mountPath,omitempty"`
+	ReadOnly  bool   `json:"readOnly,omitempty"`
 }
 
 // ListTools lists all available tools in the cluster
 func ListTools(namespace, outputFormat string, allNamespaces bool) error {
-	// Initialize dynamic client
-	dynClient, err := k8s.InitDynamicClient(namespace)
+	// Initialize tool client
+	toolClient, err := k8s.InitToolClient(namespace)
 	if err != nil {
-		return fmt.Errorf("failed to initialize dynamic client: %w", err)
+		return fmt.Errorf("failed to initialize tool client: %w", err)
 	}
 
 	// If no namespace was provided, treat it as all namespaces
 	showAllNamespaces := allNamespaces || namespace == ""
 
 	// Get tools
-	tools, err := getTools(dynClient, namespace, showAllNamespaces)
+	tools, invalid, err := toolClient.List(context.TODO(), namespace, showAllNamespaces)
 	if err != nil {
 		return fmt.Errorf("failed to get tools: %w", err)
 	}
 
+	// Add cluster tools, except those hidden by a namespaced tool with the same name
+	clusterTools, invalidClusterTools, err := toolClient.ListClusterTools(context.TODO())
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get cluster tools: %w", err)
+	}
+	// An invalid tool must not hide the others, it is only reported
+	for _, err := range append(invalid, invalidClusterTools...) {
+		fmt.Fprintf(os.Stderr, "Warning: skipped %v\n", err)
+	}
+	for i := range clusterTools {
+		shadowed := !showAllNamespaces && slices.ContainsFunc(tools, func(tool v1alpha1.Tool) bool {
+			return tool.Name == clusterTools[i].Name
+		})
+		if !shadowed {
+			tools = append(tools, *clusterTools[i].AsTool())
+		}
+	}
+
 	if len(tools) == 0 {
 		if showAllNamespaces {
 			fmt.Println("No tools found in any namespace.")
@@ -70,12 +135,8 @@ func ListTools(namespace, outputFormat string, allNamespaces bool) error {
 
 	// Convert to ToolInfo for display
 	toolInfos := make([]ToolInfo, len(tools))
-	for i, tool := range tools {
-		toolInfo, err := convertToToolInfo(tool)
-		if err != nil {
-			return fmt.Errorf("failed to convert tool %s: %w", tool.GetName(), err)
-		}
-		toolInfos[i] = toolInfo
+	for i := range tools {
+		toolInfos[i] = convertToToolInfo(&tools[i])
 	}
 
 	// Output based on format
@@ -91,114 +152,176 @@ func ListTools(namespace, outputFormat string, allNamespaces bool) error {
 	}
 }
 
-// getTools retrieves tools from Kubernetes
-func getTools(dynClient dynamic.Interface, namespace string, allNamespaces bool) ([]*unstructured.Unstructured, error) {
-	gvr := schema.GroupVersionResource{
-		Group:    "rapt.dev",
-		Version:  "v1alpha1",
-		Resource: "tools",
+// convertToToolInfo converts a tool to ToolInfo
+func convertToToolInfo(tool *v1alpha1.Tool) ToolInfo {
+	jobTemplate := tool.Spec.JobTemplate
+	toolInfo := ToolInfo{
+		Name:      tool.Name,
+		Namespace: tool.Namespace,
+		Source:    v1alpha1.ToolKind,
+		Image:     jobTemplate.Image,
+		Command:   jobTemplate.Command,
+		Args:      jobTemplate.Args,
+		Help:      tool.Spec.Help,
+		Created:   tool.CreationTimestamp.Time,
+
+		ServiceAccount:  jobTemplate.ServiceAccountName,
+		SecurityProfile: string(jobTemplate.SecurityProfile),
 	}
 
-	var listOptions metav1.ListOptions
-	if allNamespaces {
-		listOptions = metav1.ListOptions{}
-	} else {
-		listOptions = metav1.ListOptions{}
+	if tool.IsClusterTool() {
+		toolInfo.Source = v1alpha1.ClusterToolKind
 	}
-
-	var tools *unstructured.UnstructuredList
-	var err error
-
-	if allNamespaces {
-		tools, err = dynClient.Resource(gvr).List(context.TODO(), listOptions)
-	} else {
-		tools, err = dynClient.Resource(gvr).Namespace(namespace).List(context.TODO(), listOptions)
+	if tool.Spec.Extends != nil {
+		toolInfo.Extends = tool.Spec.Extends.String()
 	}
-
-	if err != nil {
-		return nil, err
+	if status := tool.Status; status.HasRuns() {
+		toolInfo.Status = &ToolRunStatus{
+			LastRunResult: string(status.LastRunResult),
+			LastJobName:   status.LastJobName,
+			SucceededRuns: status.SucceededRuns,
+			FailedRuns:    status.FailedRuns,
+		}
+		if status.LastRunTime != nil {
+			toolInfo.Status.LastRunTime = &status.LastRunTime.Time
+		}
 	}
 
-	// Convert []unstructured.Unstructured to []*unstructured.Unstructured
-	result := make([]*unstructured.Unstructured, len(tools.Items))
-	for i := range tools.Items {
-		result[i] = &tools.Items[i]
+	// Extract arguments
+	if len(tool.Spec.Arguments) > 0 {
+		toolInfo.Arguments = make([]ToolArgument, len(tool.Spec.Arguments))
+		for i, arg := range tool.Spec.Arguments {
+			toolInfo.Arguments[i] = ToolArgument{
+				Name:        arg.Name,
+				Type:        string(arg.ArgType()),
+				Description: arg.Description,
+				Required:    arg.Required,
+				Default:     arg.Default,
+				Constraints: describeArgumentConstraints(arg),
+			}
+		}
 	}
-	return result, nil
-}
 
-// convertToToolInfo converts an unstructured tool to ToolInfo
-func convertToToolInfo(tool *unstructured.Unstructured) (ToolInfo, error) {
-	toolInfo := ToolInfo{
-		Name:      tool.GetName(),
-		Namespace: tool.GetNamespace(),
-		Created:   tool.GetCreationTimestamp().Time,
+	// Extract environment variables
+	if len(jobTemplate.Env) > 0 {
+		toolInfo.Environment = make([]ToolEnvironment, len(jobTemplate.Env))
+		for i, env := range jobTemplate.Env {
+			toolInfo.Environment[i] = ToolEnvironment{
+				Name:   env.Name,
+				Value:  env.Value,
+				Source: describeEnvVarSource(env.ValueFrom),
+			}
+		}
 	}
 
-	// Extract spec
-	spec, found, err := unstructured.NestedMap(tool.Object, "spec")
-	if err != nil || !found {
-		return toolInfo, fmt.Errorf("invalid tool spec")
+	// Extract imported Secrets and ConfigMaps
+	for _, source := range jobTemplate.EnvFrom {
+		envSource := ToolEnvSource{Prefix: source.Prefix}
+		switch {
+		case source.SecretRef != nil:
+			envSource.Kind = "Secret"
+			envSource.Name = source.SecretRef.Name
+			envSource.Optional = source.SecretRef.Optional != nil && *source.SecretRef.Optional
+		case source.ConfigMapRef != nil:
+			envSource.Kind = "ConfigMap"
+			envSource.Name = source.ConfigMapRef.Name
+			envSource.Optional = source.ConfigMapRef.Optional != nil && *source.ConfigMapRef.Optional
+		default:
+			continue
+		}
+		toolInfo.EnvFrom = append(toolInfo.EnvFrom, envSource)
 	}
 
-	// Extract help text
-	if help, found, err := unstructured.NestedString(spec, "help"); err == nil && found {
-		toolInfo.Help = help
+	// Extract volumes and their mount paths in the tool container
+	for _, volume := range jobTemplate.Volumes {
+		toolVolume := ToolVolume{
+			Name:   volume.Name,
+			Source: describeVolumeSource(volume.VolumeSource),
+		}
+		for _, volumeMount := range jobTemplate.VolumeMounts {
+			if volumeMount.Name == volume.Name {
+				toolVolume.MountPath = volumeMount.MountPath
+				toolVolume.ReadOnly = volumeMount.ReadOnly
+				break
+			}
+		}
+		toolInfo.Volumes = append(toolInfo.Volumes, toolVolume)
 	}
 
-	// Extract job template
-	jobTemplate, found, err := unstructured.NestedMap(spec, "jobTemplate")
-	if err != nil || !found {
-		return toolInfo, fmt.Errorf("tool spec missing jobTemplate")
+	// Extract resources
+	if resources := jobTemplate.Resources; resources != nil && (len(resources.Requests) > 0 || len(resources.Limits) > 0) {
+		toolInfo.Resources = &ToolResources{
+			Requests: resourceListToMap(resources.Requests),
+			Limits:   resourceListToMap(resources.Limits),
+		}
 	}
 
-	// Extract image
-	if image, found, err := unstructured.NestedString(jobTemplate, "image"); err == nil && found {
-		toolInfo.Image = image
-	}
+	return toolInfo
+}
 
-	// Extract command
-	if command, found, err := unstructured.NestedStringSlice(jobTemplate, "command"); err == nil && found {
-		toolInfo.Command = command
+// describeArgumentConstraints summarizes the allowed values of an argument
+func describeArgumentConstraints(arg v1alpha1.Argument) string {
+	var constraints []string
+	if len(arg.Values) > 0 {
+		constraints = append(constraints, "one of "+strings.Join(arg.Values, "|"))
 	}
+	if arg.Min != nil {
+		constraints = append(constraints, "min "+arg.Min.String())
+	}
+	if arg.Max != nil {
+		constraints = append(constraints, "max "+arg.Max.String())
+	}
+	if arg.Pattern != "" {
+		constraints = append(constraints, "pattern "+arg.Pattern)
+	}
+	return strings.Join(constraints, ", ")
+}
 
-	// Extract arguments
-	if args, found, err := unstructured.NestedSlice(spec, "arguments"); err == nil && found {
-		toolInfo.Arguments = make([]ToolArgument, len(args))
-		for i, argItem := range args {
-			if argMap, ok := argItem.(map[string]interface{}); ok {
-				name, _ := argMap["name"].(string)
-				description, _ := argMap["description"].(string)
-				required, _ := argMap["required"].(bool)
-				defaultValue, _ := argMap["default"].(string)
-
-				toolInfo.Arguments[i] = ToolArgument{
-					Name:        name,
-					Description: description,
-					Required:    required,
-					Default:     defaultValue,
-				}
-			}
-		}
+// describeEnvVarSource describes where an environment variable value comes from
+// without resolving the referenced value
+func describeEnvVarSource(source *corev1.EnvVarSource) string {
+	switch {
+	case source == nil:
+		return ""
+	case source.SecretKeyRef != nil:
+		return fmt.Sprintf("secret %s/%s", source.SecretKeyRef.Name, source.SecretKeyRef.Key)
+	case source.ConfigMapKeyRef != nil:
+		return fmt.Sprintf("configmap %s/%s", source.ConfigMapKeyRef.Name, source.ConfigMapKeyRef.Key)
+	case source.FieldRef != nil:
+		return fmt.Sprintf("field %s", source.FieldRef.FieldPath)
+	case source.ResourceFieldRef != nil:
+		return fmt.Sprintf("resource %s", source.ResourceFieldRef.Resource)
+	default:
+		return "unknown"
 	}
+}
 
-	// Extract environment variables
-	if env, found, err := unstructured.NestedSlice(jobTemplate, "env"); err == nil && found {
-		toolInfo.Environment = make([]ToolEnvironment, len(env))
-		for i, envItem := range env {
-			if envMap, ok := envItem.(map[string]interface{}); ok {
-				name, _ := envMap["name"].(string)
-				value, _ := envMap["value"].(string)
-
-				toolInfo.Environment[i] = ToolEnvironment{
-					Name:  name,
-					Value: value,
-				}
-			}
-		}
+// describeVolumeSource describes the source of a volume
+func describeVolumeSource(source corev1.VolumeSource) string {
+	switch {
+	case source.PersistentVolumeClaim != nil:
+		return fmt.Sprintf("pvc %s", source.PersistentVolumeClaim.ClaimName)
+	case source.EmptyDir != nil:
+		return "emptyDir"
+	case source.Secret != nil:
+		return fmt.Sprintf("secret %s", source.Secret.SecretName)
+	case source.ConfigMap != nil:
+		return fmt.Sprintf("configmap %s", source.ConfigMap.Name)
+	default:
+		return "unknown"
 	}
+}
 
-	return toolInfo, nil
+// resourceListToMap converts resource quantities to their string form
+func resourceListToMap(list corev1.ResourceList) map[string]string {
+	if len(list) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(list))
+	for name, quantity := range list {
+		result[string(name)] = quantity.String()
+	}
+	return result
 }
 
 // outputTable outputs tools in table format
@@ -208,9 +331,9 @@ func outputTable(tools []ToolInfo, allNamespaces bool) error {
 
 	// Print header
 	if allNamespaces {
-		fmt.Fprintln(w, "NAME\tNAMESPACE\tIMAGE\tCOMMAND\tARGUMENTS\tCREATED")
+		fmt.Fprintln(w, "NAME\tNAMESPACE\tSOURCE\tIMAGE\tCOMMAND\tARGUMENTS\tLAST RUN\tCREATED")
 	} else {
-		fmt.Fprintln(w, "NAME\tIMAGE\tCOMMAND\tARGUMENTS\tCREATED")
+		fmt.Fprintln(w, "NAME\tSOURCE\tIMAGE\tCOMMAND\tARGUMENTS\tLAST RUN\tCREATED")
 	}
 
 	// Print tools
@@ -228,20 +351,36 @@ func outputTable(tools []ToolInfo, allNamespaces bool) error {
 			args = fmt.Sprintf("%d args", len(tool.Arguments))
 		}
 
+		lastRun := describeLastRun(tool.Status)
 		created := tool.Created.Format("2006-01-02 15:04")
 
 		if allNamespaces {
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
-				tool.Name, tool.Namespace, tool.Image, command, args, created)
+			toolNamespace := tool.Namespace
+			if toolNamespace == "" {
+				toolNamespace = "-"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				tool.Name, toolNamespace, tool.Source, tool.Image, command, args, lastRun, created)
 		} else {
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
-				tool.Name, tool.Image, command, args, created)
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				tool.Name, tool.Source, tool.Image, command, args, lastRun, created)
 		}
 	}
 
 	return nil
 }
 
+// describeLastRun returns the result of the last finished run and how long ago it started
+func describeLastRun(status *ToolRunStatus) string {
+	if status == nil || status.LastRunResult == "" {
+		return "-"
+	}
+	if status.LastRunTime == nil {
+		return status.LastRunResult
+	}
+	return fmt.Sprintf("%s %s ago", status.LastRunResult, duration.HumanDuration(time.Since(*status.LastRunTime)))
+}
+
 // outputJSON outputs tools in JSON format
 func outputJSON(tools []ToolInfo) error {
 	encoder := json.NewEncoder(os.Stdout)
@@ -257,4 +396,4 @@ func outputYAML(tools []ToolInfo) error {
 	}
 	fmt.Print(string(yamlBytes))
 	return nil
-}
\ No newline at end of file
+}
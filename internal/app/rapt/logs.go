@@ -27,7 +27,7 @@ type JobInfo struct {
 }
 
 // ShowLogs displays logs for a tool or lists previous job runs
-func ShowLogs(namespace, toolName, jobName string, follow bool, tail int) error {
+func ShowLogs(namespace, toolName, jobName, container string, follow bool, tail int) error {
 	// Initialize Kubernetes client
 	k8sClient, err := k8s.InitKubernetesClient(namespace)
 	if err != nil {
@@ -39,7 +39,7 @@ func ShowLogs(namespace, toolName, jobName string, follow bool, tail int) error
 		return listJobRuns(k8sClient, namespace, toolName)
 	} else {
 		// Show logs for specific job
-		return showJobLogs(k8sClient, namespace, jobName, follow, tail)
+		return showJobLogs(k8sClient, namespace, jobName, container, follow, tail)
 	}
 }
 
@@ -89,7 +89,7 @@ func listJobRuns(k8sClient *kubernetes.Clientset, namespace, toolName string) er
 }
 
 // showJobLogs displays logs for a specific job
-func showJobLogs(k8sClient *kubernetes.Clientset, namespace, jobName string, follow bool, tail int) error {
+func showJobLogs(k8sClient *kubernetes.Clientset, namespace, jobName, container string, follow bool, tail int) error {
 	// Get the job
 	job, err := k8sClient.BatchV1().Jobs(namespace).Get(context.TODO(), jobName, metav1.GetOptions{})
 	if err != nil {
@@ -137,7 +137,8 @@ func showJobLogs(k8sClient *kubernetes.Clientset, namespace, jobName string, fol
 
 	// Prepare log options
 	logOptions := &corev1.PodLogOptions{
-		Follow: follow,
+		Container: container,
+		Follow:    follow,
 	}
 	
 	if tail > 0 {
@@ -155,6 +156,7 @@ func showJobLogs(k8sClient *kubernetes.Clientset, namespace, jobName string, fol
 	// Display job info
 	fmt.Printf("Job: %s\n", jobName)
 	fmt.Printf("Pod: %s\n", pod.Name)
+	fmt.Printf("Container: %s\n", container)
 	fmt.Printf("Status: %s\n", getJobStatus(job))
 	fmt.Printf("Created: %s\n", job.CreationTimestamp.Format("2006-01-02 15:04:05"))
 	if follow {
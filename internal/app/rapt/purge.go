@@ -15,21 +15,23 @@ func Purge(namespace string) error {
 		return err
 	}
 
-	crd, err := k8s.LoadToolCRD()
+	crds, err := k8s.LoadCRDs()
 	if err != nil {
 		return fmt.Errorf("failed to unmarshal tool.yaml: %w", err)
 	}
 
-	crdName := crd.GetName()
-	err = k8sClient.ApiextensionsV1().CustomResourceDefinitions().Delete(context.TODO(), crdName, metav1.DeleteOptions{})
-	if err != nil {
-		if apierrors.IsNotFound(err) {
-			fmt.Println("CRD not found. Nothing to delete.")
-			return nil
+	for _, crd := range crds {
+		crdName := crd.GetName()
+		err = k8sClient.ApiextensionsV1().CustomResourceDefinitions().Delete(context.TODO(), crdName, metav1.DeleteOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				fmt.Printf("CRD %s not found. Nothing to delete.\n", crdName)
+				continue
+			}
+			return fmt.Errorf("failed to delete CRD %s: %w", crdName, err)
 		}
-		return fmt.Errorf("failed to delete CRD: %w", err)
-	}
 
-	fmt.Println("CRD deleted successfully.")
+		fmt.Printf("CRD %s deleted successfully.\n", crdName)
+	}
 	return nil
 }
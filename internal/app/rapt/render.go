@@ -0,0 +1,96 @@
+package rapt
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"codeberg.org/lig/rapt/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// invocation is the command line and environment a tool runs with for a set of argument values
+type invocation struct {
+	Command []string
+	Args    []string
+	Env     []corev1.EnvVar
+}
+
+// renderInvocation substitutes argument placeholders in the tool's command and args
+// and passes every argument to the tool the way it is declared to render.
+// Positional arguments that placeholders refer to are not appended a second time.
+// values holds the resolved argument values; arguments without a value are skipped.
+func renderInvocation(tool *v1alpha1.Tool, values map[string]string) (*invocation, error) {
+	// Every declared argument is available to placeholders, unset ones as empty strings
+	data := make(map[string]string, len(tool.Spec.Arguments))
+	for _, arg := range tool.Spec.Arguments {
+		data[arg.Name] = values[arg.Name]
+	}
+
+	command, err := renderPlaceholders(&tool.Spec, tool.Spec.JobTemplate.Command, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render command: %w", err)
+	}
+	args, err := renderPlaceholders(&tool.Spec, tool.Spec.JobTemplate.Args, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render args: %w", err)
+	}
+	result := &invocation{Command: command, Args: args}
+
+	referenced := placeholderArguments(&tool.Spec)
+	for _, arg := range tool.Spec.Arguments {
+		value, exists := values[arg.Name]
+		if !exists || (value == "" && arg.OmitEmpty()) {
+			continue
+		}
+		switch arg.RenderMode() {
+		case v1alpha1.RenderPositional:
+			if !referenced[arg.Name] {
+				result.Args = append(result.Args, value)
+			}
+		case v1alpha1.RenderFlag:
+			result.Args = append(result.Args, arg.FlagName()+"="+value)
+		case v1alpha1.RenderEnv:
+			result.Env = append(result.Env, corev1.EnvVar{Name: arg.EnvName(), Value: value})
+		case v1alpha1.RenderTemplate:
+			// Only used through placeholders
+		}
+	}
+
+	return result, nil
+}
+
+// placeholderArguments returns the names of the arguments the placeholders of command and args refer to
+func placeholderArguments(spec *v1alpha1.ToolSpec) map[string]bool {
+	referenced := make(map[string]bool)
+	for _, part := range append(slices.Clone(spec.JobTemplate.Command), spec.JobTemplate.Args...) {
+		if spec.ParsePlaceholders(part) == nil {
+			continue
+		}
+		for _, name := range v1alpha1.PlaceholderNames(part) {
+			referenced[name] = true
+		}
+	}
+	return referenced
+}
+
+// renderPlaceholders renders the {{ .argument }} placeholders of each element of a tool spec
+func renderPlaceholders(spec *v1alpha1.ToolSpec, parts []string, data map[string]string) ([]string, error) {
+	if parts == nil {
+		return nil, nil
+	}
+	rendered := make([]string, len(parts))
+	for i, part := range parts {
+		tmpl := spec.ParsePlaceholders(part)
+		if tmpl == nil {
+			rendered[i] = part
+			continue
+		}
+		var b strings.Builder
+		if err := tmpl.Execute(&b, data); err != nil {
+			return nil, err
+		}
+		rendered[i] = b.String()
+	}
+	return rendered, nil
+}
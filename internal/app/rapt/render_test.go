@@ -0,0 +1,131 @@
+package rapt
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"codeberg.org/lig/rapt/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestRenderInvocation(t *testing.T) {
+	tests := []struct {
+		name        string
+		command     []string
+		args        []string
+		arguments   []v1alpha1.Argument
+		values      map[string]string
+		wantCommand []string
+		wantArgs    []string
+		wantEnv     []corev1.EnvVar
+		wantErr     string
+	}{
+		{
+			name:      "positional",
+			args:      []string{"--verbose"},
+			arguments: []v1alpha1.Argument{{Name: "input"}, {Name: "output"}},
+			values:    map[string]string{"input": "in.csv", "output": "out.csv"},
+			wantArgs:  []string{"--verbose", "in.csv", "out.csv"},
+		},
+		{
+			name:      "flag",
+			arguments: []v1alpha1.Argument{{Name: "level", Render: &v1alpha1.ArgumentRender{As: v1alpha1.RenderFlag}}, {Name: "out", Render: &v1alpha1.ArgumentRender{As: v1alpha1.RenderFlag, Name: "-o"}}},
+			values:    map[string]string{"level": "debug", "out": "x"},
+			wantArgs:  []string{"--level=debug", "-o=x"},
+		},
+		{
+			name:      "env",
+			arguments: []v1alpha1.Argument{{Name: "log-level", Render: &v1alpha1.ArgumentRender{As: v1alpha1.RenderEnv}}, {Name: "token", Render: &v1alpha1.ArgumentRender{As: v1alpha1.RenderEnv, Name: "API_TOKEN"}}},
+			values:    map[string]string{"log-level": "debug", "token": "t"},
+			wantEnv:   []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}, {Name: "API_TOKEN", Value: "t"}},
+		},
+		{
+			name:        "template",
+			command:     []string{"sh", "-c", "migrate --target {{ .version }}"},
+			args:        []string{`--file={{ index . "input-file" }}`},
+			arguments:   []v1alpha1.Argument{{Name: "version", Render: &v1alpha1.ArgumentRender{As: v1alpha1.RenderTemplate}}, {Name: "input-file", Render: &v1alpha1.ArgumentRender{As: v1alpha1.RenderTemplate}}},
+			values:      map[string]string{"version": "42", "input-file": "a b.sql"},
+			wantCommand: []string{"sh", "-c", "migrate --target 42"},
+			wantArgs:    []string{"--file=a b.sql"},
+		},
+		{
+			name:      "positional arguments in placeholders are not appended",
+			args:      []string{"--input={{ .input }}"},
+			arguments: []v1alpha1.Argument{{Name: "input"}, {Name: "output"}},
+			values:    map[string]string{"input": "in.csv", "output": "out.csv"},
+			wantArgs:  []string{"--input=in.csv", "out.csv"},
+		},
+		{
+			name:      "unset argument renders empty and is skipped",
+			args:      []string{"[{{ .suffix }}]"},
+			arguments: []v1alpha1.Argument{{Name: "suffix"}, {Name: "level", Render: &v1alpha1.ArgumentRender{As: v1alpha1.RenderEnv}}},
+			values:    map[string]string{},
+			wantArgs:  []string{"[]"},
+		},
+		{
+			name:      "omitEmpty",
+			arguments: []v1alpha1.Argument{{Name: "label", Render: &v1alpha1.ArgumentRender{As: v1alpha1.RenderFlag, OmitEmpty: true}}, {Name: "note", Render: &v1alpha1.ArgumentRender{As: v1alpha1.RenderFlag}}},
+			values:    map[string]string{"label": "", "note": ""},
+			wantArgs:  []string{"--note="},
+		},
+		{
+			name:        "verbatim elements",
+			command:     []string{"echo", "{ .input }"},
+			arguments:   []v1alpha1.Argument{{Name: "input", Render: &v1alpha1.ArgumentRender{As: v1alpha1.RenderTemplate}}},
+			values:      map[string]string{"input": "x"},
+			wantCommand: []string{"echo", "{ .input }"},
+		},
+		{
+			name:        "placeholders for other tools are verbatim",
+			command:     []string{"kubectl", "get", "pods", "-o", "go-template={{.metadata.name}}"},
+			args:        []string{"{{ .input }}-{{ .nope }}"},
+			arguments:   []v1alpha1.Argument{{Name: "input", Render: &v1alpha1.ArgumentRender{As: v1alpha1.RenderTemplate}}},
+			values:      map[string]string{"input": "x"},
+			wantCommand: []string{"kubectl", "get", "pods", "-o", "go-template={{.metadata.name}}"},
+			wantArgs:    []string{"{{ .input }}-{{ .nope }}"},
+		},
+		{
+			name:      "error in args",
+			args:      []string{"{{ .input.name }}"},
+			arguments: []v1alpha1.Argument{{Name: "input", Render: &v1alpha1.ArgumentRender{As: v1alpha1.RenderTemplate}}},
+			values:    map[string]string{"input": "x"},
+			wantErr:   "failed to render args",
+		},
+		{
+			name:      "error in command",
+			command:   []string{"{{ .input.name }}"},
+			arguments: []v1alpha1.Argument{{Name: "input", Render: &v1alpha1.ArgumentRender{As: v1alpha1.RenderTemplate}}},
+			values:    map[string]string{"input": "x"},
+			wantErr:   "failed to render command",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := &v1alpha1.Tool{Spec: v1alpha1.ToolSpec{
+				Arguments:   tt.arguments,
+				JobTemplate: v1alpha1.JobTemplate{Image: "alpine:3.20", Command: tt.command, Args: tt.args},
+			}}
+
+			got, err := renderInvocation(tool, tt.values)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("renderInvocation() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("renderInvocation() error = %v", err)
+			}
+			if !slices.Equal(got.Command, tt.wantCommand) {
+				t.Errorf("Command = %q, want %q", got.Command, tt.wantCommand)
+			}
+			if !slices.Equal(got.Args, tt.wantArgs) {
+				t.Errorf("Args = %q, want %q", got.Args, tt.wantArgs)
+			}
+			if !slices.Equal(got.Env, tt.wantEnv) {
+				t.Errorf("Env = %v, want %v", got.Env, tt.wantEnv)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package rapt
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// ResourceSpec holds compute resource quantities given on the command line.
+// Empty fields are left unset.
+type ResourceSpec struct {
+	CPU         string
+	Memory      string
+	CPULimit    string
+	MemoryLimit string
+}
+
+// IsEmpty reports whether no resource quantity was given
+func (r ResourceSpec) IsEmpty() bool {
+	return r.CPU == "" && r.Memory == "" && r.CPULimit == "" && r.MemoryLimit == ""
+}
+
+// mergeResources applies the quantities of spec on top of base and returns the result
+func mergeResources(base *corev1.ResourceRequirements, spec ResourceSpec) (*corev1.ResourceRequirements, error) {
+	var merged corev1.ResourceRequirements
+	if base != nil {
+		base.DeepCopyInto(&merged)
+	}
+
+	entries := []struct {
+		list     *corev1.ResourceList
+		name     corev1.ResourceName
+		value    string
+		flagName string
+	}{
+		{&merged.Requests, corev1.ResourceCPU, spec.CPU, "cpu"},
+		{&merged.Requests, corev1.ResourceMemory, spec.Memory, "memory"},
+		{&merged.Limits, corev1.ResourceCPU, spec.CPULimit, "cpu-limit"},
+		{&merged.Limits, corev1.ResourceMemory, spec.MemoryLimit, "memory-limit"},
+	}
+	for _, entry := range entries {
+		if entry.value == "" {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(entry.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --%s value %q: %w", entry.flagName, entry.value, err)
+		}
+		if *entry.list == nil {
+			*entry.list = corev1.ResourceList{}
+		}
+		(*entry.list)[entry.name] = quantity
+	}
+
+	// Requests above limits are rejected by the API server, catch them early
+	for name, request := range merged.Requests {
+		if limit, ok := merged.Limits[name]; ok && request.Cmp(limit) > 0 {
+			return nil, fmt.Errorf("%s request %s exceeds limit %s", name, request.String(), limit.String())
+		}
+	}
+
+	if len(merged.Requests) == 0 && len(merged.Limits) == 0 {
+		return nil, nil
+	}
+	return &merged, nil
+}
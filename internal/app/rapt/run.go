@@ -2,20 +2,25 @@ package rapt
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
+	"path"
+	"strconv"
 	"strings"
 	"time"
 
+	"codeberg.org/lig/rapt/api/v1alpha1"
 	"codeberg.org/lig/rapt/internal/k8s"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/watch"
-	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
 )
 
 // MountSpec represents a file mount specification
@@ -24,12 +29,53 @@ type MountSpec struct {
 	ContainerPath string
 }
 
-// RunTool executes a tool by creating a Kubernetes Job
-func RunTool(namespace, toolName string, args map[string]string, envVars map[string]string, mounts []MountSpec, wait, follow bool, timeout int) error {
+// defaultTTLSecondsAfterFinished is used when neither the tool nor the run sets a TTL
+const defaultTTLSecondsAfterFinished = 300
+
+// JobOverrides holds per-run settings that take precedence over the tool's job template.
+// Nil fields keep the value from the tool definition.
+type JobOverrides struct {
+	BackoffLimit            *int32
+	ActiveDeadlineSeconds   *int64
+	TTLSecondsAfterFinished *int32
+	// FailOnExitCodes fails the job without retrying when the tool exits with one of these codes
+	FailOnExitCodes []int32
+	// Resources are merged on top of the tool's container resources
+	Resources ResourceSpec
+	// NodeSelector labels are merged on top of the tool's node selector
+	NodeSelector map[string]string
+	// Restricted applies the restricted security defaults even if the tool does not ask for them
+	Restricted bool
+}
+
+// Modes of RunTool, which decide how long it stays with the job it created
+const (
+	// RunFollow streams the logs of the job until it finishes
+	RunFollow = "follow"
+	// RunWait waits for the job to finish without streaming its logs
+	RunWait = "wait"
+	// RunDetach returns as soon as the job is created
+	RunDetach = "detach"
+)
+
+// DetachedRun identifies the job of a detached run in the JSON output of RunTool
+type DetachedRun struct {
+	Job       string `json:"job"`
+	Namespace string `json:"namespace"`
+	Tool      string `json:"tool"`
+}
+
+// RunTool executes a tool by creating a Kubernetes Job. The tool is the effective definition
+// returned by GetTool. The mode decides whether it follows the logs of the job, waits quietly
+// for it, or returns right away; a detached run only prints the job name, or a DetachedRun as
+// JSON if output is "json".
+func RunTool(namespace string, tool *v1alpha1.Tool, args map[string]string, envVars map[string]string, mounts []MountSpec, overrides JobOverrides, container, mode, output string, timeout int) error {
+	toolName := tool.Name
+
 	// Initialize clients
-	dynClient, err := k8s.InitDynamicClient(namespace)
+	toolClient, err := k8s.InitToolClient(namespace)
 	if err != nil {
-		return fmt.Errorf("failed to initialize dynamic client: %w", err)
+		return fmt.Errorf("failed to initialize tool client: %w", err)
 	}
 
 	k8sClient, err := k8s.InitKubernetesClient(namespace)
@@ -37,14 +83,30 @@ func RunTool(namespace, toolName string, args map[string]string, envVars map[str
 		return fmt.Errorf("failed to initialize kubernetes client: %w", err)
 	}
 
-	// Get the tool definition
-	tool, err := getToolDefinition(dynClient, namespace, toolName)
-	if err != nil {
-		return fmt.Errorf("failed to get tool definition: %w", err)
+	arguments := resolveArguments(tool, args)
+	if err := arguments.Err(); err != nil {
+		return err
+	}
+	if err := validateMounts(tool, mounts); err != nil {
+		return err
 	}
 
-	// Create ConfigMaps for mounted files first
+	// Build the job before creating anything, so that invalid input leaves nothing behind
 	jobName := fmt.Sprintf("%s-%s", toolName, time.Now().Format("20060102-150405"))
+	nativeSidecars := len(tool.Spec.JobTemplate.Sidecars) > 0 && supportsNativeSidecars(k8sClient)
+	job, err := createJobFromTool(tool, toolName, arguments.Values, envVars, mounts, overrides, nativeSidecars, namespace, jobName)
+	if err != nil {
+		return fmt.Errorf("failed to create job: %w", err)
+	}
+
+	// Only the job goes to stdout of a detached run, so that scripts can capture it
+	if mode == RunDetach {
+		arguments.Print(os.Stderr)
+	} else {
+		arguments.Print(os.Stdout)
+	}
+
+	// Create ConfigMaps for mounted files
 	for i, mount := range mounts {
 		// Read the local file
 		fileContent, err := os.ReadFile(mount.LocalPath)
@@ -77,86 +139,193 @@ func RunTool(namespace, toolName string, args map[string]string, envVars map[str
 		}
 	}
 
-	// Create the job
-	job, err := createJobFromTool(tool, toolName, args, envVars, mounts, namespace, jobName)
-	if err != nil {
-		return fmt.Errorf("failed to create job: %w", err)
-	}
-
 	// Create the job in Kubernetes
 	createdJob, err := k8sClient.BatchV1().Jobs(namespace).Create(context.TODO(), job, metav1.CreateOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to create job in cluster: %w", err)
 	}
 
-	fmt.Printf("Job '%s' created successfully\n", createdJob.Name)
-	fmt.Println("Streaming logs in real-time...")
-	fmt.Println("Press Ctrl+C to stop following logs (job will continue running)")
-	fmt.Println("=" + strings.Repeat("=", 50))
+	switch mode {
+	case RunDetach:
+		return printDetachedRun(createdJob, toolName, output)
+	case RunWait:
+		fmt.Printf("Job '%s' created successfully\n", createdJob.Name)
+		fmt.Printf("Waiting for job '%s' to finish...\n", createdJob.Name)
+	default:
+		fmt.Printf("Job '%s' created successfully\n", createdJob.Name)
+		fmt.Println("Streaming logs in real-time...")
+		fmt.Println("Press Ctrl+C to stop following logs (job will continue running)")
+		fmt.Println("=" + strings.Repeat("=", 50))
+	}
 
-	// Always follow logs in real-time for better user experience
-	return waitForJobCompletion(k8sClient, createdJob, true, timeout)
+	result, err := waitForJobCompletion(k8sClient, createdJob, container, mode == RunFollow, timeout)
+	if result != "" {
+		recordRun(toolClient, tool, createdJob, result)
+	}
+	if mode == RunWait && result != "" {
+		fmt.Printf("View its logs with 'rapt logs %s %s'\n", toolName, createdJob.Name)
+	}
+	return err
 }
 
-// getToolDefinition retrieves a tool definition from Kubernetes
-func getToolDefinition(dynClient dynamic.Interface, namespace, toolName string) (*unstructured.Unstructured, error) {
-	gvr := schema.GroupVersionResource{
-		Group:    "rapt.dev",
-		Version:  "v1alpha1",
-		Resource: "tools",
+// printDetachedRun prints the job of a detached run, and how to follow it to stderr
+func printDetachedRun(job *batchv1.Job, toolName, output string) error {
+	if output == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(DetachedRun{Job: job.Name, Namespace: job.Namespace, Tool: toolName}); err != nil {
+			return fmt.Errorf("failed to write the job: %w", err)
+		}
+	} else {
+		fmt.Println(job.Name)
 	}
+	fmt.Fprintf(os.Stderr, "Job '%s' created, follow its logs with 'rapt logs %s %s --follow'\n", job.Name, toolName, job.Name)
+	return nil
+}
+
+// recordRun records a finished run in the status of the tool.
+// The run itself is not affected if that fails, so failures are only reported as warnings.
+func recordRun(toolClient *k8s.ToolClient, tool *v1alpha1.Tool, job *batchv1.Job, result v1alpha1.RunResult) {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if tool.IsClusterTool() {
+			clusterTool, err := toolClient.GetClusterTool(context.TODO(), tool.Name)
+			if err != nil {
+				return err
+			}
+			clusterTool.Status.RecordRun(job.Name, job.CreationTimestamp, result)
+			clusterTool.Status.RecordArgumentCount(&clusterTool.Spec)
+			_, err = toolClient.UpdateClusterToolStatus(context.TODO(), clusterTool)
+			return err
+		}
 
-	tool, err := dynClient.Resource(gvr).Namespace(namespace).Get(context.TODO(), toolName, metav1.GetOptions{})
+		current, err := toolClient.Get(context.TODO(), tool.Namespace, tool.Name)
+		if err != nil {
+			return err
+		}
+		current.Status.RecordRun(job.Name, job.CreationTimestamp, result)
+		current.Status.RecordArgumentCount(&current.Spec)
+		_, err = toolClient.UpdateStatus(context.TODO(), current)
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("tool '%s' not found in namespace '%s'", toolName, namespace)
+		fmt.Fprintf(os.Stderr, "Warning: failed to record the run in the status of tool '%s': %v\n", tool.Name, err)
 	}
+}
 
-	return tool, nil
+// recordArgumentCount records the number of arguments of a tool in its status, which the
+// Args printer column shows. It is called after rapt writes a tool; a failure only produces a warning.
+func recordArgumentCount(toolClient *k8s.ToolClient, kind, namespace, name string) {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if kind == v1alpha1.ClusterToolKind {
+			clusterTool, err := toolClient.GetClusterTool(context.TODO(), name)
+			if err != nil {
+				return err
+			}
+			if !clusterTool.Status.RecordArgumentCount(&clusterTool.Spec) {
+				return nil
+			}
+			_, err = toolClient.UpdateClusterToolStatus(context.TODO(), clusterTool)
+			return err
+		}
+
+		current, err := toolClient.Get(context.TODO(), namespace, name)
+		if err != nil {
+			return err
+		}
+		if !current.Status.RecordArgumentCount(&current.Spec) {
+			return nil
+		}
+		_, err = toolClient.UpdateStatus(context.TODO(), current)
+		return err
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record the argument count in the status of tool '%s': %v\n", name, err)
+	}
 }
 
-// createJobFromTool creates a Kubernetes Job from a tool definition
-func createJobFromTool(tool *unstructured.Unstructured, toolName string, args map[string]string, envVars map[string]string, mounts []MountSpec, namespace, jobName string) (*batchv1.Job, error) {
-	// Extract tool spec
-	spec, found, err := unstructured.NestedMap(tool.Object, "spec")
-	if err != nil || !found {
-		return nil, fmt.Errorf("invalid tool spec")
+// GetTool returns the effective definition of a tool, merged with the tools it extends
+func GetTool(namespace, toolName string) (*v1alpha1.Tool, error) {
+	toolClient, err := k8s.InitToolClient(namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize tool client: %w", err)
 	}
 
-	jobTemplate, found, err := unstructured.NestedMap(spec, "jobTemplate")
-	if err != nil || !found {
-		return nil, fmt.Errorf("tool spec missing jobTemplate")
+	tool, err := getResolvedTool(toolClient, namespace, toolName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tool definition: %w", err)
 	}
+	return tool, nil
+}
 
-	// Extract image
-	image, found, err := unstructured.NestedString(jobTemplate, "image")
-	if err != nil || !found {
-		return nil, fmt.Errorf("tool spec missing image")
+// getToolDefinition retrieves a tool definition from Kubernetes.
+// A namespaced Tool takes precedence; otherwise a ClusterTool with the same name is used.
+func getToolDefinition(toolClient *k8s.ToolClient, namespace, toolName string) (*v1alpha1.Tool, error) {
+	tool, err := toolClient.Get(context.TODO(), namespace, toolName)
+	if err == nil {
+		return tool, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, err
 	}
 
-	// Extract command
-	var command []string
-	if cmd, found, err := unstructured.NestedStringSlice(jobTemplate, "command"); err == nil && found {
-		command = cmd
+	clusterTool, err := toolClient.GetClusterTool(context.TODO(), toolName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("tool '%s' not found in namespace '%s' or as a cluster tool", toolName, namespace)
+		}
+		return nil, err
 	}
+	return clusterTool.AsTool(), nil
+}
 
-	// Extract existing environment variables
-	var env []corev1.EnvVar
-	if envList, found, err := unstructured.NestedSlice(jobTemplate, "env"); err == nil && found {
-		for _, envItem := range envList {
-			if envMap, ok := envItem.(map[string]interface{}); ok {
-				name, _ := envMap["name"].(string)
-				value, _ := envMap["value"].(string)
-				if name != "" {
-					env = append(env, corev1.EnvVar{
-						Name:  name,
-						Value: value,
-					})
-				}
-			}
+// mountVolumeName returns the name of the volume holding the i-th --mount file.
+// It uses the reserved prefix so it cannot clash with the tool's own volumes.
+func mountVolumeName(i int) string {
+	return fmt.Sprintf("%smount-%d", v1alpha1.ReservedVolumePrefix, i)
+}
+
+// validateMounts checks that --mount targets are absolute and do not collide with each other
+// or with the volume mounts declared by the tool
+func validateMounts(tool *v1alpha1.Tool, mounts []MountSpec) error {
+	usedPaths := make(map[string]string)
+	for _, volumeMount := range tool.Spec.JobTemplate.VolumeMounts {
+		usedPaths[path.Clean(volumeMount.MountPath)] = fmt.Sprintf("volume '%s' of the tool", volumeMount.Name)
+	}
+	for _, mount := range mounts {
+		if !path.IsAbs(mount.ContainerPath) {
+			return fmt.Errorf("mount path %s must be an absolute path", mount.ContainerPath)
 		}
+		containerPath := path.Clean(mount.ContainerPath)
+		if owner, exists := usedPaths[containerPath]; exists {
+			return fmt.Errorf("mount path %s is already used by %s", mount.ContainerPath, owner)
+		}
+		usedPaths[containerPath] = fmt.Sprintf("mount of %s", mount.LocalPath)
+	}
+	return nil
+}
+
+// createJobFromTool creates a Kubernetes Job from a tool definition and resolved argument values
+func createJobFromTool(tool *v1alpha1.Tool, toolName string, values map[string]string, envVars map[string]string, mounts []MountSpec, overrides JobOverrides, nativeSidecars bool, namespace, jobName string) (*batchv1.Job, error) {
+	jobTemplate := tool.Spec.JobTemplate
+
+	// Extract existing environment variables, including Secret and ConfigMap references
+	var env []corev1.EnvVar
+	for _, envVar := range jobTemplate.Env {
+		env = append(env, *envVar.DeepCopy())
+	}
+	var envFrom []corev1.EnvFromSource
+	for _, source := range jobTemplate.EnvFrom {
+		envFrom = append(envFrom, *source.DeepCopy())
+	}
+
+	// Render tool arguments into the command line and environment
+	rendered, err := renderInvocation(tool, values)
+	if err != nil {
+		return nil, err
 	}
+	env = append(env, rendered.Env...)
 
-	// Add runtime environment variables
+	// Add runtime environment variables last so they take precedence
 	for key, value := range envVars {
 		env = append(env, corev1.EnvVar{
 			Name:  key,
@@ -164,6 +333,16 @@ func createJobFromTool(tool *unstructured.Unstructured, toolName string, args ma
 		})
 	}
 
+	// Merge container resources with the run's overrides
+	resources, err := mergeResources(jobTemplate.Resources, overrides.Resources)
+	if err != nil {
+		return nil, err
+	}
+	var containerResources corev1.ResourceRequirements
+	if resources != nil {
+		containerResources = *resources
+	}
+
 	// Handle file mounts
 	var volumeMounts []corev1.VolumeMount
 	var volumes []corev1.Volume
@@ -171,14 +350,14 @@ func createJobFromTool(tool *unstructured.Unstructured, toolName string, args ma
 	for i, mount := range mounts {
 		// Add volume mount
 		volumeMounts = append(volumeMounts, corev1.VolumeMount{
-			Name:      fmt.Sprintf("mount-%d", i),
+			Name:      mountVolumeName(i),
 			MountPath: mount.ContainerPath,
 			SubPath:   "content",
 		})
 		
 		// Add volume (ConfigMap name will be set later)
 		volumes = append(volumes, corev1.Volume{
-			Name: fmt.Sprintf("mount-%d", i),
+			Name: mountVolumeName(i),
 			VolumeSource: corev1.VolumeSource{
 				ConfigMap: &corev1.ConfigMapVolumeSource{
 					LocalObjectReference: corev1.LocalObjectReference{
@@ -189,32 +368,20 @@ func createJobFromTool(tool *unstructured.Unstructured, toolName string, args ma
 		})
 	}
 
-	// Build command arguments from tool arguments
-	var jobArgs []string
-	if toolArgs, found, err := unstructured.NestedSlice(spec, "arguments"); err == nil && found {
-		for _, argItem := range toolArgs {
-			if argMap, ok := argItem.(map[string]interface{}); ok {
-				argName, _ := argMap["name"].(string)
-				if argName != "" {
-					if value, exists := args[argName]; exists {
-						jobArgs = append(jobArgs, value)
-					} else if defaultValue, hasDefault := argMap["default"].(string); hasDefault && defaultValue != "" {
-						jobArgs = append(jobArgs, defaultValue)
-					} else if _, isRequired := argMap["required"].(bool); isRequired {
-						return nil, fmt.Errorf("required argument '%s' not provided", argName)
-					}
-				}
-			}
-		}
-	}
-
-
 	// Update volume references with ConfigMap names
 	for i := range mounts {
 		configMapName := fmt.Sprintf("%s-mount-%d", jobName, i)
 		volumes[i].VolumeSource.ConfigMap.LocalObjectReference.Name = configMapName
 	}
 
+	// Add the tool's declared volumes next to the per-run mount volumes
+	for _, volume := range jobTemplate.Volumes {
+		volumes = append(volumes, *volume.DeepCopy())
+	}
+	for _, volumeMount := range jobTemplate.VolumeMounts {
+		volumeMounts = append(volumeMounts, *volumeMount.DeepCopy())
+	}
+
 	// Create the Job
 	job := &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
@@ -226,18 +393,33 @@ func createJobFromTool(tool *unstructured.Unstructured, toolName string, args ma
 			},
 		},
 		Spec: batchv1.JobSpec{
-			TTLSecondsAfterFinished: int32Ptr(300), // Clean up after 5 minutes
+			BackoffLimit:            jobTemplate.BackoffLimit,
+			ActiveDeadlineSeconds:   jobTemplate.ActiveDeadlineSeconds,
+			TTLSecondsAfterFinished: int32Ptr(defaultTTLSecondsAfterFinished),
+			PodFailurePolicy:        jobTemplate.PodFailurePolicy.DeepCopy(),
 			Template: corev1.PodTemplateSpec{
 				Spec: corev1.PodSpec{
-					RestartPolicy: corev1.RestartPolicyNever,
+					RestartPolicy:     corev1.RestartPolicyNever,
+					NodeSelector:      copyStringMap(jobTemplate.NodeSelector),
+					Tolerations:       jobTemplate.Tolerations,
+					Affinity:          jobTemplate.Affinity.DeepCopy(),
+					PriorityClassName: jobTemplate.PriorityClassName,
+					RuntimeClassName:  jobTemplate.RuntimeClassName,
+
+					ServiceAccountName:           jobTemplate.ServiceAccountName,
+					AutomountServiceAccountToken: jobTemplate.AutomountServiceAccountToken,
+					SecurityContext:              jobTemplate.PodSecurityContext.DeepCopy(),
 					Containers: []corev1.Container{
 						{
-							Name:         "tool",
-							Image:        image,
-							Command:      command,
-							Args:         jobArgs,
-							Env:          env,
-							VolumeMounts: volumeMounts,
+							Name:            v1alpha1.ToolContainerName,
+							Image:           jobTemplate.Image,
+							Command:         rendered.Command,
+							Args:            rendered.Args,
+							Env:             env,
+							EnvFrom:         envFrom,
+							Resources:       containerResources,
+							SecurityContext: jobTemplate.SecurityContext.DeepCopy(),
+							VolumeMounts:    volumeMounts,
 						},
 					},
 					Volumes: volumes,
@@ -246,11 +428,57 @@ func createJobFromTool(tool *unstructured.Unstructured, toolName string, args ma
 		},
 	}
 
+	if revision := tool.CurrentRevision(); revision > 0 {
+		job.Labels[v1alpha1.RevisionLabel] = strconv.FormatInt(revision, 10)
+	}
+	if jobTemplate.TTLSecondsAfterFinished != nil {
+		job.Spec.TTLSecondsAfterFinished = jobTemplate.TTLSecondsAfterFinished
+	}
+	applyJobOverrides(&job.Spec, overrides)
+	addExtraContainers(&job.Spec.Template.Spec, jobTemplate, nativeSidecars)
+	if jobTemplate.SecurityProfile == v1alpha1.SecurityProfileRestricted || overrides.Restricted {
+		applyRestrictedDefaults(&job.Spec.Template.Spec)
+	}
+	for key, value := range overrides.NodeSelector {
+		if job.Spec.Template.Spec.NodeSelector == nil {
+			job.Spec.Template.Spec.NodeSelector = map[string]string{}
+		}
+		job.Spec.Template.Spec.NodeSelector[key] = value
+	}
+
 	return job, nil
 }
 
-// waitForJobCompletion waits for a job to complete and optionally follows logs
-func waitForJobCompletion(k8sClient *kubernetes.Clientset, job *batchv1.Job, follow bool, timeout int) error {
+// applyJobOverrides applies per-run overrides to a job spec built from a tool definition
+func applyJobOverrides(spec *batchv1.JobSpec, overrides JobOverrides) {
+	if overrides.BackoffLimit != nil {
+		spec.BackoffLimit = overrides.BackoffLimit
+	}
+	if overrides.ActiveDeadlineSeconds != nil {
+		spec.ActiveDeadlineSeconds = overrides.ActiveDeadlineSeconds
+	}
+	if overrides.TTLSecondsAfterFinished != nil {
+		spec.TTLSecondsAfterFinished = overrides.TTLSecondsAfterFinished
+	}
+	if len(overrides.FailOnExitCodes) > 0 {
+		if spec.PodFailurePolicy == nil {
+			spec.PodFailurePolicy = &batchv1.PodFailurePolicy{}
+		}
+		// Rules are evaluated in order, so the run's rule goes first
+		rule := batchv1.PodFailurePolicyRule{
+			Action: batchv1.PodFailurePolicyActionFailJob,
+			OnExitCodes: &batchv1.PodFailurePolicyOnExitCodesRequirement{
+				Operator: batchv1.PodFailurePolicyOnExitCodesOpIn,
+				Values:   overrides.FailOnExitCodes,
+			},
+		}
+		spec.PodFailurePolicy.Rules = append([]batchv1.PodFailurePolicyRule{rule}, spec.PodFailurePolicy.Rules...)
+	}
+}
+
+// waitForJobCompletion waits for a job to complete and optionally follows logs.
+// It returns the result of the run, or an empty result if the job did not finish.
+func waitForJobCompletion(k8sClient *kubernetes.Clientset, job *batchv1.Job, container string, follow bool, timeout int) (v1alpha1.RunResult, error) {
 	ctx := context.Background()
 	if timeout > 0 {
 		var cancel context.CancelFunc
@@ -263,13 +491,13 @@ func waitForJobCompletion(k8sClient *kubernetes.Clientset, job *batchv1.Job, fol
 		FieldSelector: fmt.Sprintf("metadata.name=%s", job.Name),
 	})
 	if err != nil {
-		return fmt.Errorf("failed to watch job: %w", err)
+		return "", fmt.Errorf("failed to watch job: %w", err)
 	}
 	defer watcher.Stop()
 
 	// Start log following if requested
 	if follow {
-		go followJobLogs(k8sClient, job)
+		go followJobLogs(k8sClient, job, container)
 	}
 
 	// Wait for job completion
@@ -284,23 +512,40 @@ func waitForJobCompletion(k8sClient *kubernetes.Clientset, job *batchv1.Job, fol
 			// Check if job is complete
 			if updatedJob.Status.Succeeded > 0 {
 				fmt.Printf("Job '%s' completed successfully\n", job.Name)
-				return nil
+				return v1alpha1.RunSucceeded, nil
 			}
 
-			if updatedJob.Status.Failed > 0 {
+			// A failed pod may still be retried, so rely on the job condition
+			if failed, reason := jobFailed(updatedJob); failed {
 				fmt.Printf("Job '%s' failed\n", job.Name)
-				return fmt.Errorf("job failed")
+				if reason != "" {
+					return v1alpha1.RunFailed, fmt.Errorf("job failed: %s", reason)
+				}
+				return v1alpha1.RunFailed, fmt.Errorf("job failed")
 			}
 		case watch.Error:
-			return fmt.Errorf("error watching job: %v", event.Object)
+			return "", fmt.Errorf("error watching job: %v", event.Object)
 		}
 	}
 
-	return fmt.Errorf("job watch ended unexpectedly")
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return "", fmt.Errorf("timed out after %d seconds waiting for job '%s', it keeps running in the cluster", timeout, job.Name)
+	}
+	return "", fmt.Errorf("job watch ended unexpectedly")
 }
 
-// followJobLogs follows the logs of a job
-func followJobLogs(k8sClient *kubernetes.Clientset, job *batchv1.Job) {
+// jobFailed reports whether a job has reached its terminal Failed condition
+func jobFailed(job *batchv1.Job) (bool, string) {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			return true, condition.Reason
+		}
+	}
+	return false, ""
+}
+
+// followJobLogs follows the logs of a job container
+func followJobLogs(k8sClient *kubernetes.Clientset, job *batchv1.Job, container string) {
 	// Wait a bit for the pod to be created
 	maxRetries := 30
 	for i := 0; i < maxRetries; i++ {
@@ -322,7 +567,8 @@ func followJobLogs(k8sClient *kubernetes.Clientset, job *batchv1.Job) {
 			if pod.Status.Phase == corev1.PodRunning || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
 				// Follow logs
 				logs, err := k8sClient.CoreV1().Pods(job.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
-					Follow: true,
+					Container: container,
+					Follow:    true,
 				}).Stream(context.TODO())
 				if err != nil {
 					fmt.Printf("Error getting logs: %v\n", err)
@@ -349,5 +595,13 @@ func followJobLogs(k8sClient *kubernetes.Clientset, job *batchv1.Job) {
 	fmt.Printf("Timeout waiting for pod to be ready for job '%s'\n", job.Name)
 }
 
+// copyStringMap returns a copy of a string map, or nil for an empty map
+func copyStringMap(m map[string]string) map[string]string {
+	if len(m) == 0 {
+		return nil
+	}
+	return maps.Clone(m)
+}
+
 // int32Ptr returns a pointer to an int32
 func int32Ptr(i int32) *int32 { return &i }
\ No newline at end of file
@@ -0,0 +1,52 @@
+package rapt
+
+import (
+	"context"
+	"testing"
+
+	"codeberg.org/lig/rapt/api/v1alpha1"
+)
+
+func TestRecordArgumentCount(t *testing.T) {
+	twoArguments := newTestTool("report", "")
+	twoArguments.Spec.Arguments = []v1alpha1.Argument{{Name: "input"}, {Name: "output"}}
+	stale := newTestTool("report", "")
+	stale.Status.ArgumentCount = int32Ptr(3)
+	clusterTool := v1alpha1.NewClusterTool("report")
+	clusterTool.Spec = *twoArguments.Spec.DeepCopy()
+
+	tests := []struct {
+		name   string
+		kind   string
+		object convertible
+		want   int32
+	}{
+		{name: "tool", kind: v1alpha1.ToolKind, object: twoArguments, want: 2},
+		{name: "stale count", kind: v1alpha1.ToolKind, object: stale, want: 0},
+		{name: "cluster tool", kind: v1alpha1.ClusterToolKind, object: clusterTool, want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toolClient := newFakeToolClient(t, tt.object)
+			recordArgumentCount(toolClient, tt.kind, "default", "report")
+
+			var status v1alpha1.ToolStatus
+			if tt.kind == v1alpha1.ClusterToolKind {
+				got, err := toolClient.GetClusterTool(context.TODO(), "report")
+				if err != nil {
+					t.Fatal(err)
+				}
+				status = got.Status
+			} else {
+				got, err := toolClient.Get(context.TODO(), "default", "report")
+				if err != nil {
+					t.Fatal(err)
+				}
+				status = got.Status
+			}
+			if status.ArgumentCount == nil || *status.ArgumentCount != tt.want {
+				t.Errorf("status.argumentCount = %v, want %d", status.ArgumentCount, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package rapt
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// applyRestrictedDefaults fills in the settings required by the restricted Pod Security Standard
+// for the pod and every container in it, init containers and sidecars included.
+// Fields already set by the tool are kept, so a tool can still opt out of individual settings.
+func applyRestrictedDefaults(podSpec *corev1.PodSpec) {
+	if podSpec.SecurityContext == nil {
+		podSpec.SecurityContext = &corev1.PodSecurityContext{}
+	}
+	podSecurity := podSpec.SecurityContext
+	if podSecurity.RunAsNonRoot == nil {
+		podSecurity.RunAsNonRoot = boolPtr(true)
+	}
+	if podSecurity.SeccompProfile == nil {
+		podSecurity.SeccompProfile = &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
+	}
+
+	for i := range podSpec.InitContainers {
+		applyRestrictedContainerDefaults(&podSpec.InitContainers[i])
+	}
+	for i := range podSpec.Containers {
+		applyRestrictedContainerDefaults(&podSpec.Containers[i])
+	}
+}
+
+// applyRestrictedContainerDefaults fills in the container settings required by the restricted Pod Security Standard
+func applyRestrictedContainerDefaults(container *corev1.Container) {
+	if container.SecurityContext == nil {
+		container.SecurityContext = &corev1.SecurityContext{}
+	}
+	security := container.SecurityContext
+	if security.AllowPrivilegeEscalation == nil {
+		security.AllowPrivilegeEscalation = boolPtr(false)
+	}
+	if security.ReadOnlyRootFilesystem == nil {
+		security.ReadOnlyRootFilesystem = boolPtr(true)
+	}
+	if security.Capabilities == nil {
+		security.Capabilities = &corev1.Capabilities{}
+	}
+	if security.Capabilities.Drop == nil {
+		security.Capabilities.Drop = []corev1.Capability{"ALL"}
+	}
+}
+
+// boolPtr returns a pointer to a bool
+func boolPtr(b bool) *bool { return &b }
@@ -0,0 +1,62 @@
+package rapt
+
+import (
+	"testing"
+
+	"codeberg.org/lig/rapt/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestRestrictedDefaultsCoverAllContainers(t *testing.T) {
+	tests := []struct {
+		name           string
+		profile        v1alpha1.SecurityProfile
+		restricted     bool
+		nativeSidecars bool
+	}{
+		{name: "profile with native sidecars", profile: v1alpha1.SecurityProfileRestricted, nativeSidecars: true},
+		{name: "profile with regular sidecars", profile: v1alpha1.SecurityProfileRestricted},
+		{name: "run override", restricted: true, nativeSidecars: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := &v1alpha1.Tool{Spec: v1alpha1.ToolSpec{JobTemplate: v1alpha1.JobTemplate{
+				Image:           "alpine:3.20",
+				SecurityProfile: tt.profile,
+				InitContainers:  []corev1.Container{{Name: "setup", Image: "alpine:3.20"}},
+				Sidecars:        []corev1.Container{{Name: "proxy", Image: "proxy:1.0"}},
+			}}}
+
+			job, err := createJobFromTool(tool, "tool", nil, nil, nil, JobOverrides{Restricted: tt.restricted}, tt.nativeSidecars, "default", "tool-1")
+			if err != nil {
+				t.Fatalf("createJobFromTool() error = %v", err)
+			}
+			podSpec := job.Spec.Template.Spec
+			if podSpec.SecurityContext == nil || podSpec.SecurityContext.RunAsNonRoot == nil || !*podSpec.SecurityContext.RunAsNonRoot {
+				t.Errorf("pod security context = %+v, want runAsNonRoot", podSpec.SecurityContext)
+			}
+			containers := append(podSpec.InitContainers, podSpec.Containers...)
+			if len(containers) != 3 {
+				t.Fatalf("got %d containers, want 3", len(containers))
+			}
+			for _, container := range containers {
+				security := container.SecurityContext
+				if security == nil || security.AllowPrivilegeEscalation == nil || *security.AllowPrivilegeEscalation ||
+					security.Capabilities == nil || len(security.Capabilities.Drop) == 0 {
+					t.Errorf("container %s security context = %+v, want restricted defaults", container.Name, security)
+				}
+			}
+		})
+	}
+}
+
+func TestRestrictedDefaultsKeepExplicitSettings(t *testing.T) {
+	podSpec := &corev1.PodSpec{Containers: []corev1.Container{{
+		Name:            "tool",
+		SecurityContext: &corev1.SecurityContext{ReadOnlyRootFilesystem: boolPtr(false)},
+	}}}
+	applyRestrictedDefaults(podSpec)
+	if got := *podSpec.Containers[0].SecurityContext.ReadOnlyRootFilesystem; got {
+		t.Errorf("readOnlyRootFilesystem = %v, want the explicit false to be kept", got)
+	}
+}
@@ -0,0 +1,234 @@
+package rapt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"codeberg.org/lig/rapt/api/v1alpha1"
+	"codeberg.org/lig/rapt/internal/k8s"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/util/retry"
+)
+
+// ToolUpdate describes changes to an existing tool. Options holds the new settings in the
+// same form as for Add; settings that are left empty keep their current value.
+type ToolUpdate struct {
+	Options ToolOptions
+	// UnsetEnv holds the names of environment variables to remove from the tool
+	UnsetEnv []string
+	// SetCommand, SetServiceAccount and SetRestricted report whether the command (including its
+	// args and shell mode), the service account and the restricted setting were given, since
+	// their empty values are changes as well
+	SetCommand        bool
+	SetServiceAccount bool
+	SetRestricted     bool
+}
+
+// isEmpty reports whether the update changes nothing
+func (u ToolUpdate) isEmpty() bool {
+	o := u.Options
+	return o.Image == "" && len(o.Env) == 0 && len(o.SecretEnv) == 0 && len(o.ConfigMapEnv) == 0 &&
+		len(o.EnvFromSecrets) == 0 && len(o.EnvFromConfigMaps) == 0 && o.Resources.IsEmpty() &&
+		len(u.UnsetEnv) == 0 && !u.SetCommand && !u.SetServiceAccount && !u.SetRestricted
+}
+
+// UpdateTool changes the given settings of an existing tool and keeps all others.
+// The tool is updated with optimistic concurrency: if it is changed by someone else at
+// the same time, the update is applied again on top of that change.
+// With cluster set, the name refers to a ClusterTool.
+func UpdateTool(namespace, toolName string, update ToolUpdate, cluster, dryRun bool) error {
+	if update.isEmpty() {
+		return errors.New("no changes given, see 'rapt update --help' for the settings that can be changed")
+	}
+
+	toolClient, err := k8s.InitToolClient(namespace)
+	if err != nil {
+		return fmt.Errorf("failed to initialize tool client: %w", err)
+	}
+	if cluster {
+		return updateClusterTool(toolClient, toolName, update, dryRun)
+	}
+
+	unchanged := false
+	var updated *v1alpha1.Tool
+	var notSet []string
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		tool, err := toolClient.Get(context.TODO(), namespace, toolName)
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("tool '%s' not found in namespace '%s'", toolName, namespace)
+		}
+		if err != nil {
+			return err
+		}
+		changed := tool.DeepCopy()
+		if notSet, err = applyToolUpdate(&changed.Spec.JobTemplate, update); err != nil {
+			return err
+		}
+		if unchanged = equality.Semantic.DeepEqual(changed.Spec, tool.Spec); unchanged {
+			return nil
+		}
+		if err := changed.Validate(); err != nil {
+			return err
+		}
+		if dryRun {
+			updated = changed
+			return nil
+		}
+
+		// Keep changes made outside of rapt, so that they can be restored later
+		tool, err = saveToolRevision(toolClient, tool, "changed outside of rapt")
+		if err != nil {
+			return err
+		}
+		tool.Spec = changed.Spec
+		updated, err = toolClient.Update(context.TODO(), tool)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update tool %s: %w", toolName, err)
+	}
+	warnNotSet(notSet)
+
+	if unchanged {
+		fmt.Printf("Tool '%s' already has these settings, nothing to update.\n", toolName)
+		return nil
+	}
+	if dryRun {
+		printArgv(updated.Spec.JobTemplate.Command)
+		return k8s.PrintToolYAML(updated)
+	}
+	if _, err := saveToolRevision(toolClient, updated, "rapt update"); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: tool '%s' was updated, but its revision was not recorded: %v\n", toolName, err)
+	}
+	recordArgumentCount(toolClient, v1alpha1.ToolKind, namespace, toolName)
+
+	fmt.Printf("Successfully updated tool '%s' in namespace '%s'\n", toolName, namespace)
+	return nil
+}
+
+// updateClusterTool changes the given settings of an existing ClusterTool
+func updateClusterTool(toolClient *k8s.ToolClient, toolName string, update ToolUpdate, dryRun bool) error {
+	unchanged := false
+	var updated *v1alpha1.ClusterTool
+	var notSet []string
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		clusterTool, err := toolClient.GetClusterTool(context.TODO(), toolName)
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("cluster tool '%s' not found", toolName)
+		}
+		if err != nil {
+			return err
+		}
+		changed := clusterTool.DeepCopy()
+		if notSet, err = applyToolUpdate(&changed.Spec.JobTemplate, update); err != nil {
+			return err
+		}
+		if unchanged = equality.Semantic.DeepEqual(changed.Spec, clusterTool.Spec); unchanged {
+			return nil
+		}
+		if err := changed.AsTool().Validate(); err != nil {
+			return err
+		}
+		if dryRun {
+			updated = changed
+			return nil
+		}
+		updated, err = toolClient.UpdateClusterTool(context.TODO(), changed)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update cluster tool %s: %w", toolName, err)
+	}
+	warnNotSet(notSet)
+
+	if unchanged {
+		fmt.Printf("Cluster tool '%s' already has these settings, nothing to update.\n", toolName)
+		return nil
+	}
+	if dryRun {
+		printArgv(updated.Spec.JobTemplate.Command)
+		return k8s.PrintClusterToolYAML(updated)
+	}
+
+	recordArgumentCount(toolClient, v1alpha1.ClusterToolKind, "", toolName)
+
+	fmt.Printf("Successfully updated cluster tool '%s'\n", toolName)
+	return nil
+}
+
+// applyToolUpdate changes a job template as described by update and returns the names of
+// the environment variables to unset that were not set.
+// Environment variables are removed first, then the given ones replace those with the same name.
+func applyToolUpdate(jobTemplate *v1alpha1.JobTemplate, update ToolUpdate) ([]string, error) {
+	opts := update.Options
+	if opts.Image != "" {
+		jobTemplate.Image = opts.Image
+	}
+	if update.SetCommand {
+		command, err := buildCommand(opts)
+		if err != nil {
+			return nil, err
+		}
+		jobTemplate.Command = command
+	}
+
+	var notSet []string
+	for _, name := range update.UnsetEnv {
+		before := len(jobTemplate.Env)
+		jobTemplate.Env = slices.DeleteFunc(jobTemplate.Env, func(env corev1.EnvVar) bool { return env.Name == name })
+		if len(jobTemplate.Env) == before {
+			notSet = append(notSet, name)
+		}
+	}
+	envVars, err := buildEnv(opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, envVar := range envVars {
+		i := slices.IndexFunc(jobTemplate.Env, func(env corev1.EnvVar) bool { return env.Name == envVar.Name })
+		if i < 0 {
+			jobTemplate.Env = append(jobTemplate.Env, envVar)
+			continue
+		}
+		jobTemplate.Env[i] = envVar
+	}
+	for _, envFrom := range buildEnvFrom(opts) {
+		if !slices.ContainsFunc(jobTemplate.EnvFrom, func(existing corev1.EnvFromSource) bool {
+			return equality.Semantic.DeepEqual(existing, envFrom)
+		}) {
+			jobTemplate.EnvFrom = append(jobTemplate.EnvFrom, envFrom)
+		}
+	}
+
+	if !opts.Resources.IsEmpty() {
+		requirements, err := mergeResources(jobTemplate.Resources, opts.Resources)
+		if err != nil {
+			return nil, err
+		}
+		jobTemplate.Resources = requirements
+	}
+
+	if update.SetServiceAccount {
+		jobTemplate.ServiceAccountName = opts.ServiceAccount
+	}
+	if update.SetRestricted {
+		if opts.Restricted {
+			jobTemplate.SecurityProfile = v1alpha1.SecurityProfileRestricted
+		} else if jobTemplate.SecurityProfile == v1alpha1.SecurityProfileRestricted {
+			jobTemplate.SecurityProfile = ""
+		}
+	}
+	return notSet, nil
+}
+
+// warnNotSet warns about environment variables that were to be unset but are not set on the tool
+func warnNotSet(names []string) {
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "Warning: environment variable %s is not set on the tool, nothing to unset\n", name)
+	}
+}
@@ -0,0 +1,133 @@
+package rapt
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"codeberg.org/lig/rapt/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+func TestApplyToolUpdate(t *testing.T) {
+	secretRef := func(name string) corev1.EnvFromSource {
+		return corev1.EnvFromSource{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: name}}}
+	}
+
+	tests := []struct {
+		name        string
+		jobTemplate v1alpha1.JobTemplate
+		update      ToolUpdate
+		want        v1alpha1.JobTemplate
+		wantNotSet  []string
+		wantErr     string
+	}{
+		{
+			name:        "image only",
+			jobTemplate: v1alpha1.JobTemplate{Image: "alpine:3.20", Command: []string{"echo"}},
+			update:      ToolUpdate{Options: ToolOptions{Image: "alpine:3.21"}},
+			want:        v1alpha1.JobTemplate{Image: "alpine:3.21", Command: []string{"echo"}},
+		},
+		{
+			name:        "set env replaces in place",
+			jobTemplate: v1alpha1.JobTemplate{Env: []corev1.EnvVar{{Name: "A", Value: "1"}, {Name: "B", Value: "2"}}},
+			update:      ToolUpdate{Options: ToolOptions{Env: []string{"A=3", "C=4"}}},
+			want:        v1alpha1.JobTemplate{Env: []corev1.EnvVar{{Name: "A", Value: "3"}, {Name: "B", Value: "2"}, {Name: "C", Value: "4"}}},
+		},
+		{
+			name:        "unset then set moves the variable to the end",
+			jobTemplate: v1alpha1.JobTemplate{Env: []corev1.EnvVar{{Name: "A", Value: "1"}, {Name: "B", Value: "2"}}},
+			update:      ToolUpdate{Options: ToolOptions{Env: []string{"A=3"}}, UnsetEnv: []string{"A"}},
+			want:        v1alpha1.JobTemplate{Env: []corev1.EnvVar{{Name: "B", Value: "2"}, {Name: "A", Value: "3"}}},
+		},
+		{
+			name:        "unset a variable that is not set",
+			jobTemplate: v1alpha1.JobTemplate{Env: []corev1.EnvVar{{Name: "A", Value: "1"}}},
+			update:      ToolUpdate{UnsetEnv: []string{"B", "A", "C"}},
+			want:        v1alpha1.JobTemplate{Env: []corev1.EnvVar{}},
+			wantNotSet:  []string{"B", "C"},
+		},
+		{
+			name:        "env from is not added twice",
+			jobTemplate: v1alpha1.JobTemplate{EnvFrom: []corev1.EnvFromSource{secretRef("db")}},
+			update:      ToolUpdate{Options: ToolOptions{EnvFromSecrets: []string{"db", "api"}}},
+			want:        v1alpha1.JobTemplate{EnvFrom: []corev1.EnvFromSource{secretRef("db"), secretRef("api")}},
+		},
+		{
+			name:        "command is kept unless given",
+			jobTemplate: v1alpha1.JobTemplate{Command: []string{"echo", "hello"}},
+			update:      ToolUpdate{Options: ToolOptions{Command: "ignored"}, SetServiceAccount: true},
+			want:        v1alpha1.JobTemplate{Command: []string{"echo", "hello"}},
+		},
+		{
+			name:        "new command",
+			jobTemplate: v1alpha1.JobTemplate{Command: []string{"echo", "hello"}},
+			update:      ToolUpdate{Options: ToolOptions{Command: "printf '%s\\n'", CommandArgs: []string{"hi"}}, SetCommand: true},
+			want:        v1alpha1.JobTemplate{Command: []string{"printf", "%s\\n", "hi"}},
+		},
+		{
+			name:        "empty command clears it",
+			jobTemplate: v1alpha1.JobTemplate{Command: []string{"echo", "hello"}},
+			update:      ToolUpdate{SetCommand: true},
+			want:        v1alpha1.JobTemplate{},
+		},
+		{
+			name:        "shell without a command",
+			jobTemplate: v1alpha1.JobTemplate{Command: []string{"echo"}},
+			update:      ToolUpdate{Options: ToolOptions{Shell: true}, SetCommand: true},
+			wantErr:     "--shell requires a --command to run",
+		},
+		{
+			name:        "invalid env",
+			jobTemplate: v1alpha1.JobTemplate{},
+			update:      ToolUpdate{Options: ToolOptions{Env: []string{"A"}}},
+			wantErr:     "invalid environment variable format: A",
+		},
+		{
+			name:        "empty service account clears it",
+			jobTemplate: v1alpha1.JobTemplate{ServiceAccountName: "reporter"},
+			update:      ToolUpdate{SetServiceAccount: true},
+			want:        v1alpha1.JobTemplate{},
+		},
+		{
+			name:        "restricted",
+			jobTemplate: v1alpha1.JobTemplate{},
+			update:      ToolUpdate{Options: ToolOptions{Restricted: true}, SetRestricted: true},
+			want:        v1alpha1.JobTemplate{SecurityProfile: v1alpha1.SecurityProfileRestricted},
+		},
+		{
+			name:        "restricted=false",
+			jobTemplate: v1alpha1.JobTemplate{SecurityProfile: v1alpha1.SecurityProfileRestricted},
+			update:      ToolUpdate{SetRestricted: true},
+			want:        v1alpha1.JobTemplate{},
+		},
+		{
+			name:        "restricted is kept unless given",
+			jobTemplate: v1alpha1.JobTemplate{SecurityProfile: v1alpha1.SecurityProfileRestricted},
+			update:      ToolUpdate{Options: ToolOptions{Image: "alpine:3.21"}},
+			want:        v1alpha1.JobTemplate{Image: "alpine:3.21", SecurityProfile: v1alpha1.SecurityProfileRestricted},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobTemplate := tt.jobTemplate
+			notSet, err := applyToolUpdate(&jobTemplate, tt.update)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("applyToolUpdate() error = %v, want an error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyToolUpdate() error = %v", err)
+			}
+			if !equality.Semantic.DeepEqual(jobTemplate, tt.want) {
+				t.Errorf("applyToolUpdate() job template = %+v, want %+v", jobTemplate, tt.want)
+			}
+			if !slices.Equal(notSet, tt.wantNotSet) {
+				t.Errorf("applyToolUpdate() not set = %q, want %q", notSet, tt.wantNotSet)
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package rapt
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"codeberg.org/lig/rapt/api/v1alpha1"
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/kballard/go-shellquote"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// argumentTypes are the types offered for an argument, in the order they are listed
+var argumentTypes = []string{
+	string(v1alpha1.ArgumentTypeString),
+	string(v1alpha1.ArgumentTypeInt),
+	string(v1alpha1.ArgumentTypeBool),
+	string(v1alpha1.ArgumentTypeEnum),
+	string(v1alpha1.ArgumentTypeDuration),
+	string(v1alpha1.ArgumentTypePath),
+}
+
+// AddInteractive asks for the settings of a new tool, shows the resulting YAML and creates
+// the tool after confirmation. Settings given as flags are offered as the defaults.
+// In dry-run mode the YAML is shown and nothing is created.
+func AddInteractive(namespace, name string, opts ToolOptions, cluster, dryRun bool) error {
+	if name == "" {
+		if err := survey.AskOne(&survey.Input{Message: "Tool name:"}, &name, survey.WithValidator(survey.Required), survey.WithValidator(validateToolName)); err != nil {
+			return fmt.Errorf("failed to read the tool name: %w", err)
+		}
+	}
+
+	if err := survey.AskOne(&survey.Input{
+		Message: "Container image:",
+		Default: opts.Image,
+	}, &opts.Image, survey.WithValidator(survey.Required)); err != nil {
+		return fmt.Errorf("failed to read the image: %w", err)
+	}
+	if err := survey.AskOne(&survey.Input{
+		Message: "Command (optional):",
+		Default: opts.Command,
+		Help:    "The command run in the container, split with shell quoting rules, e.g. \"sh -c 'echo $HOME'\". Leave it empty to run the ENTRYPOINT of the image.",
+	}, &opts.Command, survey.WithValidator(validateCommand)); err != nil {
+		return fmt.Errorf("failed to read the command: %w", err)
+	}
+	env, err := askEnv()
+	if err != nil {
+		return err
+	}
+	opts.Env = append(opts.Env, env...)
+
+	arguments, err := askArguments()
+	if err != nil {
+		return err
+	}
+	var help string
+	if err := survey.AskOne(&survey.Multiline{
+		Message: "Help text (optional):",
+		Help:    "Shown by 'rapt help' and 'rapt describe'.",
+	}, &help); err != nil {
+		return fmt.Errorf("failed to read the help text: %w", err)
+	}
+	if err := askResources(&opts.Resources); err != nil {
+		return err
+	}
+
+	tool, err := buildTool(namespace, name, opts)
+	if err != nil {
+		return err
+	}
+	tool.Spec.Arguments = arguments
+	tool.Spec.Help = strings.TrimSpace(help)
+	if err := tool.Validate(); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	if err := createTool(namespace, tool, cluster, true); err != nil {
+		return err
+	}
+	if dryRun {
+		return nil
+	}
+	fmt.Println()
+
+	confirmed := false
+	if err := survey.AskOne(&survey.Confirm{Message: fmt.Sprintf("Create tool '%s'?", name), Default: true}, &confirmed); err != nil {
+		return fmt.Errorf("failed to get confirmation: %w", err)
+	}
+	if !confirmed {
+		fmt.Println("Tool creation cancelled.")
+		return nil
+	}
+	return createTool(namespace, tool, cluster, false)
+}
+
+// askEnv asks for plain environment variables until an empty answer is given
+func askEnv() ([]string, error) {
+	var env []string
+	for {
+		var e string
+		if err := survey.AskOne(&survey.Input{
+			Message: "Environment variable (NAME=VALUE, leave empty to continue):",
+		}, &e, survey.WithValidator(validateEnvAssignment)); err != nil {
+			return nil, fmt.Errorf("failed to read the environment variable: %w", err)
+		}
+		if e == "" {
+			return env, nil
+		}
+		env = append(env, e)
+	}
+}
+
+// askArguments asks for the arguments of the tool until no further argument is wanted
+func askArguments() ([]v1alpha1.Argument, error) {
+	var arguments []v1alpha1.Argument
+	for {
+		another := false
+		message := "Add an argument?"
+		if len(arguments) > 0 {
+			message = "Add another argument?"
+		}
+		if err := survey.AskOne(&survey.Confirm{Message: message}, &another); err != nil {
+			return nil, fmt.Errorf("failed to read the arguments: %w", err)
+		}
+		if !another {
+			return arguments, nil
+		}
+
+		arg, err := askArgument(arguments)
+		if err != nil {
+			return nil, err
+		}
+		arguments = append(arguments, arg)
+	}
+}
+
+// askArgument asks for the settings of one argument; its name must differ from those of previous
+func askArgument(previous []v1alpha1.Argument) (v1alpha1.Argument, error) {
+	var arg v1alpha1.Argument
+	if err := survey.AskOne(&survey.Input{Message: "  Name:"}, &arg.Name, survey.WithValidator(survey.Required), survey.WithValidator(validateArgumentName), survey.WithValidator(func(ans interface{}) error {
+		name, _ := ans.(string)
+		if slices.ContainsFunc(previous, func(a v1alpha1.Argument) bool { return a.Name == name }) {
+			return fmt.Errorf("argument %s is already defined", name)
+		}
+		return nil
+	})); err != nil {
+		return arg, fmt.Errorf("failed to read the argument name: %w", err)
+	}
+
+	var argType string
+	if err := survey.AskOne(&survey.Select{
+		Message: "  Type:",
+		Options: argumentTypes,
+		Default: string(v1alpha1.ArgumentTypeString),
+	}, &argType); err != nil {
+		return arg, fmt.Errorf("failed to read the argument type: %w", err)
+	}
+	if argType != string(v1alpha1.ArgumentTypeString) {
+		arg.Type = v1alpha1.ArgumentType(argType)
+	}
+	if arg.Type == v1alpha1.ArgumentTypeEnum {
+		var values string
+		if err := survey.AskOne(&survey.Input{Message: "  Allowed values (comma-separated):"}, &values, survey.WithValidator(survey.Required)); err != nil {
+			return arg, fmt.Errorf("failed to read the allowed values: %w", err)
+		}
+		for _, value := range strings.Split(values, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				arg.Values = append(arg.Values, value)
+			}
+		}
+	}
+
+	if err := survey.AskOne(&survey.Confirm{Message: "  Required?"}, &arg.Required); err != nil {
+		return arg, fmt.Errorf("failed to read whether the argument is required: %w", err)
+	}
+	if !arg.Required {
+		if err := survey.AskOne(&survey.Input{Message: "  Default value (optional):"}, &arg.Default, survey.WithValidator(func(ans interface{}) error {
+			value, _ := ans.(string)
+			if value == "" {
+				return nil
+			}
+			return arg.ValidateValue(value)
+		})); err != nil {
+			return arg, fmt.Errorf("failed to read the default value: %w", err)
+		}
+	}
+	if err := survey.AskOne(&survey.Input{Message: "  Description (optional):"}, &arg.Description); err != nil {
+		return arg, fmt.Errorf("failed to read the argument description: %w", err)
+	}
+	return arg, nil
+}
+
+// askResources asks for the resource requests and limits of the tool container
+func askResources(resources *ResourceSpec) error {
+	questions := []struct {
+		message string
+		value   *string
+	}{
+		{"CPU request (optional, e.g. 500m):", &resources.CPU},
+		{"Memory request (optional, e.g. 256Mi):", &resources.Memory},
+		{"CPU limit (optional):", &resources.CPULimit},
+		{"Memory limit (optional):", &resources.MemoryLimit},
+	}
+	for _, q := range questions {
+		if err := survey.AskOne(&survey.Input{Message: q.message, Default: *q.value}, q.value, survey.WithValidator(validateQuantity)); err != nil {
+			return fmt.Errorf("failed to read the resources: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateToolName checks that an answer is a valid Kubernetes object name
+func validateToolName(ans interface{}) error {
+	name, _ := ans.(string)
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// validateArgumentName checks that an answer is an argument name that the Tool CRD accepts
+func validateArgumentName(ans interface{}) error {
+	name, _ := ans.(string)
+	return v1alpha1.ValidateArgumentName(name)
+}
+
+// validateCommand checks that an answer can be split with shell quoting rules
+func validateCommand(ans interface{}) error {
+	command, _ := ans.(string)
+	if _, err := shellquote.Split(command); err != nil {
+		return fmt.Errorf("invalid command: %w", err)
+	}
+	return nil
+}
+
+// validateEnvAssignment checks that a non-empty answer is in NAME=VALUE format
+func validateEnvAssignment(ans interface{}) error {
+	e, _ := ans.(string)
+	if e == "" {
+		return nil
+	}
+	parts := strings.SplitN(e, "=", 2)
+	if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+		return errors.New("expected NAME=VALUE")
+	}
+	return nil
+}
+
+// validateQuantity checks that a non-empty answer is a resource quantity
+func validateQuantity(ans interface{}) error {
+	value, _ := ans.(string)
+	if value == "" {
+		return nil
+	}
+	if _, err := resource.ParseQuantity(value); err != nil {
+		return fmt.Errorf("invalid quantity %q, e.g. 500m or 256Mi", value)
+	}
+	return nil
+}
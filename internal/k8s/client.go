@@ -92,3 +92,17 @@ func InitKubernetesClient(namespace string) (*kubernetes.Clientset, error) {
 
 	return k8sClient, nil
 }
+
+// CurrentNamespace returns the given namespace, or the namespace of the current kubeconfig context if it is empty
+func CurrentNamespace(namespace string) (string, error) {
+	if namespace != "" {
+		return namespace, nil
+	}
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{})
+	namespace, _, err := kubeConfig.Namespace()
+	if err != nil {
+		return "", err
+	}
+	return namespace, nil
+}
@@ -3,9 +3,11 @@ package k8s
 import (
 	_ "embed"
 	"fmt"
+	"strings"
+
+	"codeberg.org/lig/rapt/api/v1alpha1"
 
 	apiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/yaml"
 )
 
@@ -20,6 +22,108 @@ func LoadToolCRD() (*apiv1.CustomResourceDefinition, error) {
 	return &crd, nil
 }
 
+// LoadClusterToolCRD returns the CRD of the cluster-scoped ClusterTool kind.
+// It is derived from the Tool CRD so that both kinds always share the same schema.
+func LoadClusterToolCRD() (*apiv1.CustomResourceDefinition, error) {
+	crd, err := LoadToolCRD()
+	if err != nil {
+		return nil, err
+	}
+
+	crd.Name = v1alpha1.ClusterToolResource.Resource + "." + v1alpha1.GroupVersion.Group
+	crd.Spec.Scope = apiv1.ClusterScoped
+	crd.Spec.Names = apiv1.CustomResourceDefinitionNames{
+		Plural:     v1alpha1.ClusterToolResource.Resource,
+		Singular:   strings.ToLower(v1alpha1.ClusterToolKind),
+		Kind:       v1alpha1.ClusterToolKind,
+		ShortNames: []string{"crt"},
+		Categories: crd.Spec.Names.Categories,
+	}
+
+	// A ClusterTool cannot depend on a namespaced Tool
+	for i := range crd.Spec.Versions {
+		spec := crd.Spec.Versions[i].Schema.OpenAPIV3Schema.Properties["spec"]
+		extends := spec.Properties["extends"]
+		extends.XValidations = append(extends.XValidations, apiv1.ValidationRule{
+			Rule:    "has(self.kind) && self.kind == 'ClusterTool'",
+			Message: "a ClusterTool can only extend another ClusterTool",
+		})
+		spec.Properties["extends"] = extends
+		crd.Spec.Versions[i].Schema.OpenAPIV3Schema.Properties["spec"] = spec
+	}
+	return crd, nil
+}
+
+// LoadToolRevisionCRD returns the CRD of the ToolRevision kind.
+// Its spec uses the schema of the Tool spec, and its revision number cannot be changed.
+func LoadToolRevisionCRD() (*apiv1.CustomResourceDefinition, error) {
+	crd, err := LoadToolCRD()
+	if err != nil {
+		return nil, err
+	}
+
+	crd.Name = v1alpha1.ToolRevisionResource.Resource + "." + v1alpha1.GroupVersion.Group
+	crd.Spec.Scope = apiv1.NamespaceScoped
+	crd.Spec.Names = apiv1.CustomResourceDefinitionNames{
+		Plural:     v1alpha1.ToolRevisionResource.Resource,
+		Singular:   strings.ToLower(v1alpha1.ToolRevisionKind),
+		Kind:       v1alpha1.ToolRevisionKind,
+		ShortNames: []string{"rtrev"},
+		Categories: crd.Spec.Names.Categories,
+	}
+
+	minRevision := 1.0
+	for i := range crd.Spec.Versions {
+		version := &crd.Spec.Versions[i]
+		toolSchema := version.Schema.OpenAPIV3Schema
+		revisionSpec := toolSchema.Properties["spec"]
+		revisionSpec.XValidations = append(revisionSpec.XValidations, apiv1.ValidationRule{
+			Rule:    "self == oldSelf",
+			Message: "the spec of a revision is immutable",
+		})
+		version.Schema.OpenAPIV3Schema = &apiv1.JSONSchemaProps{
+			Type:     "object",
+			Required: []string{"revision", "spec"},
+			Properties: map[string]apiv1.JSONSchemaProps{
+				"revision": {
+					Type:        "integer",
+					Format:      "int64",
+					Description: "Number of the revision, starting at 1.",
+					Minimum:     &minRevision,
+					XValidations: apiv1.ValidationRules{{
+						Rule:    "self == oldSelf",
+						Message: "revision is immutable",
+					}},
+				},
+				"spec": revisionSpec,
+			},
+		}
+		version.AdditionalPrinterColumns = []apiv1.CustomResourceColumnDefinition{
+			{Name: "Revision", Type: "integer", JSONPath: ".revision"},
+			{Name: "Age", Type: "date", JSONPath: ".metadata.creationTimestamp"},
+		}
+		version.Subresources = nil
+	}
+	return crd, nil
+}
+
+// LoadCRDs returns every CRD that Rapt installs
+func LoadCRDs() ([]*apiv1.CustomResourceDefinition, error) {
+	toolCRD, err := LoadToolCRD()
+	if err != nil {
+		return nil, err
+	}
+	clusterToolCRD, err := LoadClusterToolCRD()
+	if err != nil {
+		return nil, err
+	}
+	toolRevisionCRD, err := LoadToolRevisionCRD()
+	if err != nil {
+		return nil, err
+	}
+	return []*apiv1.CustomResourceDefinition{toolCRD, clusterToolCRD, toolRevisionCRD}, nil
+}
+
 // PrintCRDYAML prints a CustomResourceDefinition as YAML to stdout
 func PrintCRDYAML(crd *apiv1.CustomResourceDefinition) error {
 	yamlBytes, err := yaml.Marshal(crd)
@@ -31,11 +135,29 @@ func PrintCRDYAML(crd *apiv1.CustomResourceDefinition) error {
 }
 
 // PrintToolYAML prints a Tool custom resource as YAML to stdout
-func PrintToolYAML(tool *unstructured.Unstructured) error {
-	yamlBytes, err := yaml.Marshal(tool.Object)
+func PrintToolYAML(tool *v1alpha1.Tool) error {
+	u, err := tool.ToUnstructured()
+	if err != nil {
+		return err
+	}
+	yamlBytes, err := yaml.Marshal(u.Object)
 	if err != nil {
 		return fmt.Errorf("failed to marshal Tool to YAML: %w", err)
 	}
 	fmt.Print(string(yamlBytes))
 	return nil
 }
+
+// PrintClusterToolYAML prints a ClusterTool custom resource as YAML to stdout
+func PrintClusterToolYAML(clusterTool *v1alpha1.ClusterTool) error {
+	u, err := clusterTool.ToUnstructured()
+	if err != nil {
+		return err
+	}
+	yamlBytes, err := yaml.Marshal(u.Object)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ClusterTool to YAML: %w", err)
+	}
+	fmt.Print(string(yamlBytes))
+	return nil
+}
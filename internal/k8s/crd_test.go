@@ -0,0 +1,32 @@
+package k8s
+
+import "testing"
+
+func TestLoadToolRevisionCRDIsImmutable(t *testing.T) {
+	crd, err := LoadToolRevisionCRD()
+	if err != nil {
+		t.Fatalf("LoadToolRevisionCRD() error = %v", err)
+	}
+	for _, version := range crd.Spec.Versions {
+		for _, field := range []string{"revision", "spec"} {
+			immutable := false
+			for _, rule := range version.Schema.OpenAPIV3Schema.Properties[field].XValidations {
+				immutable = immutable || rule.Rule == "self == oldSelf"
+			}
+			if !immutable {
+				t.Errorf("version %s: %s has no self == oldSelf rule", version.Name, field)
+			}
+		}
+	}
+
+	// The rule must not leak into the Tool CRD, whose spec changes
+	toolCRD, err := LoadToolCRD()
+	if err != nil {
+		t.Fatalf("LoadToolCRD() error = %v", err)
+	}
+	for _, rule := range toolCRD.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"].XValidations {
+		if rule.Rule == "self == oldSelf" {
+			t.Errorf("the Tool spec is immutable")
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	apiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+)
+
+// rootFields are handled by the API server itself rather than by the CRD schema
+var rootFields = []string{"apiVersion", "kind", "metadata"}
+
+// SchemaValidator checks objects against the OpenAPI schema of a CRD without contacting the API server.
+// CEL rules (x-kubernetes-validations) are not evaluated; the typed Validate methods cover them.
+type SchemaValidator struct {
+	schema *spec.Schema
+}
+
+// NewSchemaValidator returns a validator for the schema of the served version of a CRD
+func NewSchemaValidator(crd *apiv1.CustomResourceDefinition) (*SchemaValidator, error) {
+	for _, version := range crd.Spec.Versions {
+		if !version.Served || version.Schema == nil {
+			continue
+		}
+		raw, err := json.Marshal(version.Schema.OpenAPIV3Schema)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal schema of CRD %s: %w", crd.Name, err)
+		}
+		var schema spec.Schema
+		if err := json.Unmarshal(raw, &schema); err != nil {
+			return nil, fmt.Errorf("failed to parse schema of CRD %s: %w", crd.Name, err)
+		}
+		return &SchemaValidator{schema: &schema}, nil
+	}
+	return nil, fmt.Errorf("CRD %s has no served version with a schema", crd.Name)
+}
+
+// Validate returns every problem of the object: unknown fields, missing required fields,
+// values of the wrong type and values outside their allowed range
+func (v *SchemaValidator) Validate(obj map[string]interface{}) []error {
+	var errs []error
+	for key, value := range obj {
+		if slices.Contains(rootFields, key) {
+			continue
+		}
+		property, declared := v.schema.Properties[key]
+		if !declared {
+			errs = append(errs, fmt.Errorf("%s: unknown field", key))
+			continue
+		}
+		errs = append(errs, unknownFields(key, value, &property)...)
+	}
+
+	result := validate.NewSchemaValidator(v.schema, nil, "", strfmt.Default).Validate(obj)
+	return append(errs, result.Errors...)
+}
+
+// Default sets the defaults declared by the schema on the object, as the API server does on write
+func (v *SchemaValidator) Default(obj map[string]interface{}) {
+	applyDefaults(obj, v.schema)
+}
+
+// unknownFields returns an error for every field below path that the schema does not declare.
+// The API server would drop such fields silently, which usually hides a typo.
+func unknownFields(path string, value interface{}, schema *spec.Schema) []error {
+	if preserve, _ := schema.Extensions.GetBool("x-kubernetes-preserve-unknown-fields"); preserve {
+		return nil
+	}
+	if intOrString, _ := schema.Extensions.GetBool("x-kubernetes-int-or-string"); intOrString {
+		return nil
+	}
+
+	var errs []error
+	switch value := value.(type) {
+	case map[string]interface{}:
+		if schema.AdditionalProperties != nil {
+			if schema.AdditionalProperties.Schema == nil {
+				return nil
+			}
+			for key, item := range value {
+				errs = append(errs, unknownFields(path+"."+key, item, schema.AdditionalProperties.Schema)...)
+			}
+			return errs
+		}
+		for key, item := range value {
+			property, declared := schema.Properties[key]
+			if !declared {
+				errs = append(errs, fmt.Errorf("%s.%s: unknown field", path, key))
+				continue
+			}
+			errs = append(errs, unknownFields(path+"."+key, item, &property)...)
+		}
+	case []interface{}:
+		if schema.Items == nil || schema.Items.Schema == nil {
+			return nil
+		}
+		for i, item := range value {
+			errs = append(errs, unknownFields(fmt.Sprintf("%s[%d]", path, i), item, schema.Items.Schema)...)
+		}
+	}
+	return errs
+}
+
+// applyDefaults sets the schema defaults of missing fields, recursing into objects and arrays
+func applyDefaults(value interface{}, schema *spec.Schema) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for name, property := range schema.Properties {
+			if _, exists := value[name]; !exists && property.Default != nil {
+				value[name] = runtime.DeepCopyJSONValue(normalizeJSON(property.Default))
+			}
+			if item, exists := value[name]; exists {
+				applyDefaults(item, &property)
+			}
+		}
+		if schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil {
+			for _, item := range value {
+				applyDefaults(item, schema.AdditionalProperties.Schema)
+			}
+		}
+	case []interface{}:
+		if schema.Items != nil && schema.Items.Schema != nil {
+			for _, item := range value {
+				applyDefaults(item, schema.Items.Schema)
+			}
+		}
+	}
+}
+
+// normalizeJSON converts numbers decoded by encoding/json into the int64 or float64 values
+// used by unstructured objects
+func normalizeJSON(value interface{}) interface{} {
+	if number, ok := value.(float64); ok && number == float64(int64(number)) {
+		return int64(number)
+	}
+	return value
+}
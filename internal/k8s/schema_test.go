@@ -0,0 +1,193 @@
+package k8s
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"sigs.k8s.io/yaml"
+)
+
+// newToolSchemaValidator returns the schema validator of the Tool CRD
+func newToolSchemaValidator(t *testing.T) *SchemaValidator {
+	t.Helper()
+	crd, err := LoadToolCRD()
+	if err != nil {
+		t.Fatalf("LoadToolCRD() error = %v", err)
+	}
+	validator, err := NewSchemaValidator(crd)
+	if err != nil {
+		t.Fatalf("NewSchemaValidator() error = %v", err)
+	}
+	return validator
+}
+
+// decodeObject decodes a YAML manifest into the map of an unstructured object
+func decodeObject(t *testing.T, manifest string) map[string]interface{} {
+	t.Helper()
+	var obj map[string]interface{}
+	if err := yaml.Unmarshal([]byte(manifest), &obj); err != nil {
+		t.Fatalf("yaml.Unmarshal() error = %v", err)
+	}
+	return obj
+}
+
+func TestSchemaValidatorValidate(t *testing.T) {
+	validator := newToolSchemaValidator(t)
+
+	tests := []struct {
+		name string
+		// root holds extra fields of the object besides spec
+		root string
+		spec string
+		// want lists a substring of each expected error, in any order
+		want []string
+	}{
+		{
+			name: "valid",
+			spec: "jobTemplate: {image: alpine:3.20, args: [echo]}",
+		},
+		{
+			name: "unknown root field",
+			root: "specs: {}",
+			spec: "jobTemplate: {image: alpine:3.20}",
+			want: []string{"specs: unknown field"},
+		},
+		{
+			name: "unknown nested field",
+			spec: "jobTemplate: {image: alpine:3.20, imagePullPolicy: Always}",
+			want: []string{"spec.jobTemplate.imagePullPolicy: unknown field"},
+		},
+		{
+			name: "unknown field in an array item",
+			spec: "arguments: [{name: input, descripton: typo}]\njobTemplate: {image: alpine:3.20}",
+			want: []string{"spec.arguments[0].descripton: unknown field"},
+		},
+		{
+			name: "additional properties are not unknown",
+			spec: "jobTemplate: {image: alpine:3.20, nodeSelector: {disktype: ssd}, resources: {limits: {cpu: 500m, memory: 1}}}",
+		},
+		{
+			name: "preserved unknown fields",
+			spec: "jobTemplate: {image: alpine:3.20, affinity: {nodeAffinity: {anything: goes}}}",
+		},
+		{
+			name: "int or string",
+			spec: "arguments: [{name: count, type: int, min: 1, max: \"10\"}]\njobTemplate: {image: alpine:3.20}",
+		},
+		{
+			name: "missing required field",
+			spec: "arguments: [{description: no name}]\njobTemplate: {image: alpine:3.20}",
+			want: []string{"spec.arguments[0].name in body is required"},
+		},
+		{
+			name: "wrong type",
+			spec: "jobTemplate: {image: alpine:3.20, args: echo}",
+			want: []string{"spec.jobTemplate.args"},
+		},
+		{
+			name: "value outside enum",
+			spec: "arguments: [{name: input, type: float}]\njobTemplate: {image: alpine:3.20}",
+			want: []string{"spec.arguments[0].type in body should be one of"},
+		},
+		{
+			name: "invalid quantity",
+			spec: "jobTemplate: {image: alpine:3.20, resources: {limits: {memory: lots}}}",
+			want: []string{"spec.jobTemplate.resources.limits.memory"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := decodeObject(t, "apiVersion: rapt.dev/v1alpha1\nkind: Tool\nmetadata: {name: echo}\n"+tt.root)
+			obj["spec"] = decodeObject(t, tt.spec)
+
+			var got []string
+			for _, err := range validator.Validate(obj) {
+				got = append(got, err.Error())
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Validate() = %q, want %d errors containing %q", got, len(tt.want), tt.want)
+			}
+			for _, want := range tt.want {
+				found := false
+				for _, err := range got {
+					found = found || strings.Contains(err, want)
+				}
+				if !found {
+					t.Errorf("Validate() = %q, want an error containing %q", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSchemaValidatorDefault(t *testing.T) {
+	validator := newToolSchemaValidator(t)
+
+	tests := []struct {
+		name string
+		spec string
+		want string
+	}{
+		{
+			name: "no defaults apply",
+			spec: "jobTemplate: {image: alpine:3.20}",
+			want: "jobTemplate: {image: alpine:3.20}",
+		},
+		{
+			name: "argument defaults",
+			spec: "arguments: [{name: input}, {name: verbose, type: bool, required: true}]\njobTemplate: {image: alpine:3.20}",
+			want: "arguments: [{name: input, type: string, required: false}, {name: verbose, type: bool, required: true}]\njobTemplate: {image: alpine:3.20}",
+		},
+		{
+			name: "nested default inside a set object",
+			spec: "arguments: [{name: input, type: string, required: false, render: {name: INPUT}}]\njobTemplate: {image: alpine:3.20}",
+			want: "arguments: [{name: input, type: string, required: false, render: {as: positional, name: INPUT}}]\njobTemplate: {image: alpine:3.20}",
+		},
+		{
+			name: "explicit values are kept",
+			spec: "arguments: [{name: input, type: path, required: false, render: {as: env}}]\nextends: {kind: ClusterTool, name: base}\njobTemplate: {}",
+			want: "arguments: [{name: input, type: path, required: false, render: {as: env}}]\nextends: {kind: ClusterTool, name: base}\njobTemplate: {}",
+		},
+		{
+			name: "extends kind",
+			spec: "extends: {name: base}\njobTemplate: {}",
+			want: "extends: {kind: Tool, name: base}\njobTemplate: {}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := map[string]interface{}{"spec": decodeObject(t, tt.spec)}
+			validator.Default(obj)
+			if want := decodeObject(t, tt.want); !reflect.DeepEqual(obj["spec"], want) {
+				t.Errorf("Default() spec = %v, want %v", obj["spec"], want)
+			}
+		})
+	}
+}
+
+func TestApplyDefaultsNormalizesNumbers(t *testing.T) {
+	schema := &spec.Schema{SchemaProps: spec.SchemaProps{
+		Properties: map[string]spec.Schema{
+			"retries": {SchemaProps: spec.SchemaProps{Default: float64(3)}},
+			"ratio":   {SchemaProps: spec.SchemaProps{Default: 0.5}},
+			"limits": {SchemaProps: spec.SchemaProps{
+				AdditionalProperties: &spec.SchemaOrBool{Schema: &spec.Schema{SchemaProps: spec.SchemaProps{
+					Properties: map[string]spec.Schema{"unit": {SchemaProps: spec.SchemaProps{Default: "Mi"}}},
+				}}},
+			}},
+		},
+	}}
+
+	obj := map[string]interface{}{"limits": map[string]interface{}{"memory": map[string]interface{}{}}}
+	applyDefaults(obj, schema)
+	want := map[string]interface{}{
+		"retries": int64(3),
+		"ratio":   0.5,
+		"limits":  map[string]interface{}{"memory": map[string]interface{}{"unit": "Mi"}},
+	}
+	if !reflect.DeepEqual(obj, want) {
+		t.Errorf("applyDefaults() = %v, want %v", obj, want)
+	}
+}
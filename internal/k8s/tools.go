@@ -0,0 +1,299 @@
+package k8s
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"codeberg.org/lig/rapt/api/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/dynamic"
+)
+
+// FieldManager is the field manager rapt uses for server-side apply
+const FieldManager = "rapt"
+
+// ToolClient reads and writes typed Tool, ClusterTool and ToolRevision resources through the dynamic client
+type ToolClient struct {
+	resource         dynamic.NamespaceableResourceInterface
+	clusterResource  dynamic.NamespaceableResourceInterface
+	revisionResource dynamic.NamespaceableResourceInterface
+}
+
+// NewToolClient returns a ToolClient backed by the given dynamic client
+func NewToolClient(dynClient dynamic.Interface) *ToolClient {
+	return &ToolClient{
+		resource:         dynClient.Resource(v1alpha1.ToolResource),
+		clusterResource:  dynClient.Resource(v1alpha1.ClusterToolResource),
+		revisionResource: dynClient.Resource(v1alpha1.ToolRevisionResource),
+	}
+}
+
+// InitToolClient initializes a ToolClient using the same kubeconfig logic as InitDynamicClient.
+func InitToolClient(namespace string) (*ToolClient, error) {
+	dynClient, err := InitDynamicClient(namespace)
+	if err != nil {
+		return nil, err
+	}
+	return NewToolClient(dynClient), nil
+}
+
+// Get returns the named Tool from the namespace
+func (c *ToolClient) Get(ctx context.Context, namespace, name string) (*v1alpha1.Tool, error) {
+	u, err := c.resource.Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return v1alpha1.ToolFromUnstructured(u)
+}
+
+// List returns the Tools in the namespace, or in all namespaces when allNamespaces is set.
+// Invalid tools are left out of the list and returned as the second value, one error each.
+func (c *ToolClient) List(ctx context.Context, namespace string, allNamespaces bool) ([]v1alpha1.Tool, []error, error) {
+	var resource dynamic.ResourceInterface = c.resource
+	if !allNamespaces {
+		resource = c.resource.Namespace(namespace)
+	}
+
+	list, err := resource.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	tools, invalid := v1alpha1.ToolsFromUnstructuredList(list)
+	return tools, invalid, nil
+}
+
+// ListNames returns the names of the Tools in the namespace without decoding their specs,
+// so that malformed tools can still be found and removed
+func (c *ToolClient) ListNames(ctx context.Context, namespace string) ([]string, error) {
+	list, err := c.resource.Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(list.Items))
+	for i, item := range list.Items {
+		names[i] = item.GetName()
+	}
+	return names, nil
+}
+
+// Create stores a new Tool in the cluster
+func (c *ToolClient) Create(ctx context.Context, tool *v1alpha1.Tool) (*v1alpha1.Tool, error) {
+	u, err := tool.ToUnstructured()
+	if err != nil {
+		return nil, err
+	}
+	created, err := c.resource.Namespace(tool.Namespace).Create(ctx, u, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return v1alpha1.ToolFromUnstructured(created)
+}
+
+// Delete removes the named Tool from the namespace
+func (c *ToolClient) Delete(ctx context.Context, namespace, name string) error {
+	return c.resource.Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+}
+
+// Update replaces a Tool in the cluster. The update fails if the Tool was changed
+// since its resourceVersion was read. The stored Tool is returned without validating it,
+// so that the revision of a tool that was made invalid outside of rapt can be recorded.
+func (c *ToolClient) Update(ctx context.Context, tool *v1alpha1.Tool) (*v1alpha1.Tool, error) {
+	u, err := tool.ToUnstructured()
+	if err != nil {
+		return nil, err
+	}
+	updated, err := c.resource.Namespace(tool.Namespace).Update(ctx, u, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return v1alpha1.DecodeTool(updated)
+}
+
+// UpdateStatus replaces the status of a Tool through its status subresource
+func (c *ToolClient) UpdateStatus(ctx context.Context, tool *v1alpha1.Tool) (*v1alpha1.Tool, error) {
+	u, err := tool.ToUnstructured()
+	if err != nil {
+		return nil, err
+	}
+	updated, err := c.resource.Namespace(tool.Namespace).UpdateStatus(ctx, u, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return v1alpha1.ToolFromUnstructured(updated)
+}
+
+// ListRevisions returns the revisions of a Tool, oldest first.
+// Revisions left over from a deleted Tool with the same name are skipped.
+func (c *ToolClient) ListRevisions(ctx context.Context, tool *v1alpha1.Tool) ([]v1alpha1.ToolRevision, error) {
+	list, err := c.revisionResource.Namespace(tool.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.Set{v1alpha1.ToolLabel: tool.Name}.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	all, err := v1alpha1.ToolRevisionsFromUnstructuredList(list)
+	if err != nil {
+		return nil, err
+	}
+
+	var revisions []v1alpha1.ToolRevision
+	for _, revision := range all {
+		if slices.ContainsFunc(revision.OwnerReferences, func(owner metav1.OwnerReference) bool {
+			return owner.UID == tool.UID
+		}) {
+			revisions = append(revisions, revision)
+		}
+	}
+	slices.SortFunc(revisions, func(a, b v1alpha1.ToolRevision) int {
+		return cmp.Compare(a.Revision, b.Revision)
+	})
+	return revisions, nil
+}
+
+// CreateRevision stores a new ToolRevision in the cluster
+func (c *ToolClient) CreateRevision(ctx context.Context, revision *v1alpha1.ToolRevision) error {
+	u, err := revision.ToUnstructured()
+	if err != nil {
+		return err
+	}
+	_, err = c.revisionResource.Namespace(revision.Namespace).Create(ctx, u, metav1.CreateOptions{})
+	return err
+}
+
+// GetClusterTool returns the named ClusterTool
+func (c *ToolClient) GetClusterTool(ctx context.Context, name string) (*v1alpha1.ClusterTool, error) {
+	u, err := c.clusterResource.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return v1alpha1.ClusterToolFromUnstructured(u)
+}
+
+// ListClusterTools returns all ClusterTools.
+// Invalid ClusterTools are left out of the list and returned as the second value, one error each.
+func (c *ToolClient) ListClusterTools(ctx context.Context) ([]v1alpha1.ClusterTool, []error, error) {
+	list, err := c.clusterResource.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	clusterTools, invalid := v1alpha1.ClusterToolsFromUnstructuredList(list)
+	return clusterTools, invalid, nil
+}
+
+// CreateClusterTool stores a new ClusterTool in the cluster
+func (c *ToolClient) CreateClusterTool(ctx context.Context, clusterTool *v1alpha1.ClusterTool) (*v1alpha1.ClusterTool, error) {
+	u, err := clusterTool.ToUnstructured()
+	if err != nil {
+		return nil, err
+	}
+	created, err := c.clusterResource.Create(ctx, u, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return v1alpha1.ClusterToolFromUnstructured(created)
+}
+
+// UpdateClusterTool replaces a ClusterTool in the cluster. The update fails if the ClusterTool
+// was changed since its resourceVersion was read.
+func (c *ToolClient) UpdateClusterTool(ctx context.Context, clusterTool *v1alpha1.ClusterTool) (*v1alpha1.ClusterTool, error) {
+	u, err := clusterTool.ToUnstructured()
+	if err != nil {
+		return nil, err
+	}
+	updated, err := c.clusterResource.Update(ctx, u, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return v1alpha1.ClusterToolFromUnstructured(updated)
+}
+
+// UpdateClusterToolStatus replaces the status of a ClusterTool through its status subresource
+func (c *ToolClient) UpdateClusterToolStatus(ctx context.Context, clusterTool *v1alpha1.ClusterTool) (*v1alpha1.ClusterTool, error) {
+	u, err := clusterTool.ToUnstructured()
+	if err != nil {
+		return nil, err
+	}
+	updated, err := c.clusterResource.UpdateStatus(ctx, u, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return v1alpha1.ClusterToolFromUnstructured(updated)
+}
+
+// DeleteClusterTool removes the named ClusterTool
+func (c *ToolClient) DeleteClusterTool(ctx context.Context, name string) error {
+	return c.clusterResource.Delete(ctx, name, metav1.DeleteOptions{})
+}
+
+// GetObject returns the named Tool or ClusterTool without decoding it,
+// so that an object that is not a valid tool can still be replaced
+func (c *ToolClient) GetObject(ctx context.Context, kind, namespace, name string) (*unstructured.Unstructured, error) {
+	resource, err := c.resourceFor(kind, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return resource.Get(ctx, name, metav1.GetOptions{})
+}
+
+// ListObjects returns the Tools in the namespace or all ClusterTools without decoding them
+func (c *ToolClient) ListObjects(ctx context.Context, kind, namespace string) ([]unstructured.Unstructured, error) {
+	resource, err := c.resourceFor(kind, namespace)
+	if err != nil {
+		return nil, err
+	}
+	list, err := resource.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// CreateObject stores a new Tool or ClusterTool in the cluster
+func (c *ToolClient) CreateObject(ctx context.Context, u *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	resource, err := c.resourceFor(u.GetKind(), u.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	return resource.Create(ctx, u, metav1.CreateOptions{})
+}
+
+// UpdateObject replaces a Tool or ClusterTool with the given object. The update fails if
+// the object was changed since the resourceVersion of u was read.
+func (c *ToolClient) UpdateObject(ctx context.Context, u *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	resource, err := c.resourceFor(u.GetKind(), u.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	return resource.Update(ctx, u, metav1.UpdateOptions{})
+}
+
+// ApplyObject creates or updates a Tool or ClusterTool manifest with server-side apply.
+// rapt takes ownership of every field set in the manifest, also from other field managers.
+func (c *ToolClient) ApplyObject(ctx context.Context, u *unstructured.Unstructured, dryRun bool) (*unstructured.Unstructured, error) {
+	resource, err := c.resourceFor(u.GetKind(), u.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	options := metav1.ApplyOptions{FieldManager: FieldManager, Force: true}
+	if dryRun {
+		options.DryRun = []string{metav1.DryRunAll}
+	}
+	return resource.Apply(ctx, u.GetName(), u, options)
+}
+
+// resourceFor returns the resource interface of a Tool in the namespace or of a ClusterTool
+func (c *ToolClient) resourceFor(kind, namespace string) (dynamic.ResourceInterface, error) {
+	switch kind {
+	case v1alpha1.ToolKind:
+		return c.resource.Namespace(namespace), nil
+	case v1alpha1.ClusterToolKind:
+		return c.clusterResource, nil
+	default:
+		return nil, fmt.Errorf("unsupported kind %q, expected %s or %s", kind, v1alpha1.ToolKind, v1alpha1.ClusterToolKind)
+	}
+}